@@ -0,0 +1,69 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bitcoin implements the derivation of Bitcoin addresses from
+// secp256k1 public keys.
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+	"github.com/wdvxdr1123/secp256k1/internal/base58"
+	"github.com/wdvxdr1123/secp256k1/internal/bech32"
+	"github.com/wdvxdr1123/secp256k1/internal/ripemd160"
+)
+
+// P2PKH address version bytes.
+const (
+	p2pkhMainnet = 0x00
+	p2pkhTestnet = 0x6f
+)
+
+var errInfinity = errors.New("bitcoin: public key is the point at infinity")
+
+// hash160 returns RIPEMD160(SHA256(b)).
+func hash160(b []byte) [ripemd160.Size]byte {
+	h := sha256.Sum256(b)
+	return ripemd160.Sum(h[:])
+}
+
+// compressedKeyHash returns the HASH160 of the compressed encoding of pub, or
+// an error if pub is the point at infinity.
+func compressedKeyHash(pub *secp256k1.Point) ([ripemd160.Size]byte, error) {
+	if pub.IsInfinity() == 1 {
+		return [ripemd160.Size]byte{}, errInfinity
+	}
+	return hash160(pub.BytesCompressed()), nil
+}
+
+// AddressP2PKH returns the pay-to-public-key-hash address of pub, the
+// base58check encoding of a version byte and the HASH160 of the compressed
+// encoding of pub. mainnet selects the mainnet (1...) rather than the testnet
+// (m... or n...) version byte. It returns an error if pub is the point at
+// infinity.
+func AddressP2PKH(pub *secp256k1.Point, mainnet bool) (string, error) {
+	h, err := compressedKeyHash(pub)
+	if err != nil {
+		return "", err
+	}
+	version := byte(p2pkhTestnet)
+	if mainnet {
+		version = p2pkhMainnet
+	}
+	return base58.CheckEncode(append([]byte{version}, h[:]...)), nil
+}
+
+// AddressP2WPKH returns the mainnet pay-to-witness-public-key-hash address of
+// pub, the bech32 encoding of the version 0 witness program made of the
+// HASH160 of the compressed encoding of pub, as specified in BIP 173. It
+// returns an error if pub is the point at infinity.
+func AddressP2WPKH(pub *secp256k1.Point) (string, error) {
+	h, err := compressedKeyHash(pub)
+	if err != nil {
+		return "", err
+	}
+	return bech32.EncodeSegwit("bc", 0, h[:])
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bitcoin
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		priv                   string
+		p2pkh, testnet, p2wpkh string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001",
+			"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+			"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+			"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+			"1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", "", ""},
+	}
+	for _, tt := range tests {
+		priv, err := hex.DecodeString(tt.priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := secp256k1.NewPoint().ScalarBaseMult(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := AddressP2PKH(pub, true); err != nil || got != tt.p2pkh {
+			t.Errorf("AddressP2PKH(%s * G, mainnet) = %q, %v, want %s", tt.priv, got, err, tt.p2pkh)
+		}
+		if tt.testnet != "" {
+			if got, err := AddressP2PKH(pub, false); err != nil || got != tt.testnet {
+				t.Errorf("AddressP2PKH(%s * G, testnet) = %q, %v, want %s", tt.priv, got, err, tt.testnet)
+			}
+		}
+		if tt.p2wpkh != "" {
+			if got, err := AddressP2WPKH(pub); err != nil || got != tt.p2wpkh {
+				t.Errorf("AddressP2WPKH(%s * G) = %q, %v, want %s", tt.priv, got, err, tt.p2wpkh)
+			}
+		}
+	}
+
+	inf := secp256k1.NewPoint()
+	if _, err := AddressP2PKH(inf, true); !errors.Is(err, errInfinity) {
+		t.Errorf("AddressP2PKH of the point at infinity: got error %v", err)
+	}
+	if _, err := AddressP2WPKH(inf); !errors.Is(err, errInfinity) {
+		t.Errorf("AddressP2WPKH of the point at infinity: got error %v", err)
+	}
+}
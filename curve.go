@@ -0,0 +1,56 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+// CurvePoint is a generic constraint for the point types of prime order short
+// Weierstrass curves, such as *Point. Its method set is that of the nistec
+// point types, so that other curves can be plugged in without wrappers.
+type CurvePoint[T any] interface {
+	Set(T) T
+	SetGenerator() T
+	Bytes() []byte
+	BytesX() ([]byte, error)
+	BytesCompressed() []byte
+	SetBytes([]byte) (T, error)
+	Add(T, T) T
+	Double(T) T
+	Select(T, T, int) T
+	ScalarMult(T, []byte) (T, error)
+	ScalarBaseMult([]byte) (T, error)
+}
+
+// Curve is a prime order short Weierstrass curve with points of type P. It
+// lets code such as key exchange and signature schemes be written once for
+// several curves.
+type Curve[P CurvePoint[P]] interface {
+	// Name returns the standard name of the curve, such as "secp256k1".
+	Name() string
+
+	// NewPoint returns a new point set to the point at infinity.
+	NewPoint() P
+
+	// NewGenerator returns a new point set to the canonical generator.
+	NewGenerator() P
+
+	// Order returns the big-endian encoding of the order of the group, which
+	// is also the length of the scalars accepted by ScalarBaseMult.
+	Order() []byte
+
+	// ScalarBaseMult returns a new point set to scalar times the generator.
+	ScalarBaseMult(scalar []byte) (P, error)
+}
+
+type s256Curve struct{}
+
+// S256 returns the secp256k1 Curve, whose points are *Point values.
+func S256() Curve[*Point] { return s256Curve{} }
+
+func (s256Curve) Name() string         { return "secp256k1" }
+func (s256Curve) NewPoint() *Point     { return NewPoint() }
+func (s256Curve) NewGenerator() *Point { return NewGenerator() }
+func (s256Curve) Order() []byte        { return Order() }
+func (s256Curve) ScalarBaseMult(scalar []byte) (*Point, error) {
+	return NewPoint().ScalarBaseMult(scalar)
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+var _ CurvePoint[*Point] = (*Point)(nil)
+
+// randomCurveScalar returns a random scalar in [1, n-1] for c.
+func randomCurveScalar[P CurvePoint[P]](t *testing.T, c Curve[P]) []byte {
+	n := new(big.Int).SetBytes(c.Order())
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.Add(k, big.NewInt(1)).FillBytes(make([]byte, len(c.Order())))
+}
+
+// testCurve checks generic properties of c only through the Curve and
+// CurvePoint interfaces.
+func testCurve[P CurvePoint[P]](t *testing.T, c Curve[P]) {
+	a, b := randomCurveScalar(t, c), randomCurveScalar(t, c)
+
+	// Diffie-Hellman: a * (b * G) == b * (a * G).
+	aG, err := c.ScalarBaseMult(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bG, err := c.ScalarBaseMult(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abG, err := c.NewPoint().ScalarMult(bG, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baG, err := c.NewPoint().ScalarMult(aG, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(abG.Bytes(), baG.Bytes()) {
+		t.Errorf("%s: a * bG != b * aG", c.Name())
+	}
+
+	// G + G == 2G, and G + O == G.
+	g := c.NewGenerator()
+	two := make([]byte, len(c.Order()))
+	two[len(two)-1] = 2
+	twoG, err := c.ScalarBaseMult(two)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c.NewPoint().Add(g, g).Bytes(), twoG.Bytes()) {
+		t.Errorf("%s: G + G != 2G", c.Name())
+	}
+	if !bytes.Equal(c.NewPoint().Double(g).Bytes(), twoG.Bytes()) {
+		t.Errorf("%s: Double(G) != 2G", c.Name())
+	}
+	if !bytes.Equal(c.NewPoint().Add(g, c.NewPoint()).Bytes(), g.Bytes()) {
+		t.Errorf("%s: G + O != G", c.Name())
+	}
+
+	// (n - 1) * G + G == O.
+	nMinusOne := new(big.Int).SetBytes(c.Order())
+	nMinusOne.Sub(nMinusOne, big.NewInt(1))
+	p, err := c.ScalarBaseMult(nMinusOne.FillBytes(make([]byte, len(c.Order()))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Add(p, g).Bytes(); !bytes.Equal(got, []byte{0}) {
+		t.Errorf("%s: n * G = %x, want infinity", c.Name(), got)
+	}
+
+	// Encodings round-trip.
+	for _, enc := range [][]byte{aG.Bytes(), aG.BytesCompressed()} {
+		q, err := c.NewPoint().SetBytes(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(q.Bytes(), aG.Bytes()) {
+			t.Errorf("%s: %x doesn't round-trip", c.Name(), enc)
+		}
+	}
+}
+
+func TestCurve(t *testing.T) {
+	c := S256()
+	if c.Name() != "secp256k1" {
+		t.Errorf("Name() = %q", c.Name())
+	}
+	if !bytes.Equal(c.Order(), Order()) {
+		t.Errorf("Order() = %x", c.Order())
+	}
+	testCurve(t, c)
+}
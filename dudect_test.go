@@ -0,0 +1,155 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build dudect
+
+package secp256k1
+
+// This file implements an advisory constant-time check in the style of dudect
+// (Reparaz, Balasch and Verbauwhede, "Dude, is my code constant time?"). It
+// times an operation on two classes of inputs, a fixed one and random ones,
+// and applies Welch's t-test to the two timing distributions. A large |t|
+// means the timings depend on the input.
+//
+// The test is statistical and machine dependent: passing it does not prove
+// constant time, and a noisy machine can make it fail. It is meant to catch
+// gross data-dependent branches or memory accesses, and only runs with
+//
+//	go test -tags dudect -run Dudect -v
+
+import (
+	"crypto/rand"
+	"flag"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+var dudectSamples = flag.Int("dudect.samples", 200000, "number of timing samples per dudect test")
+
+// dudectThreshold is the |t| value above which the timings are considered
+// input dependent. dudect reports "definitely not constant time" above 10.
+const dudectThreshold = 10
+
+// welch accumulates the mean and variance of the two classes with Welford's
+// online algorithm.
+type welch struct {
+	n, mean, m2 [2]float64
+}
+
+func (w *welch) push(class int, x float64) {
+	w.n[class]++
+	d := x - w.mean[class]
+	w.mean[class] += d / w.n[class]
+	w.m2[class] += d * (x - w.mean[class])
+}
+
+func (w *welch) t() float64 {
+	v0 := w.m2[0] / (w.n[0] - 1)
+	v1 := w.m2[1] / (w.n[1] - 1)
+	return (w.mean[0] - w.mean[1]) / math.Sqrt(v0/w.n[0]+v1/w.n[1])
+}
+
+// dudect runs op on the inputs returned by input for a random interleaving of
+// the two classes, and fails if Welch's t statistic exceeds dudectThreshold,
+// either on all timings or after cropping the slowest ones, which are mostly
+// due to interrupts and scheduling.
+func dudect(t *testing.T, input func(class int) func()) {
+	samples := *dudectSamples
+	classes := make([]byte, samples)
+	if _, err := rand.Read(classes); err != nil {
+		t.Fatal(err)
+	}
+	ops := make([]func(), samples)
+	for i := range ops {
+		ops[i] = input(int(classes[i] & 1))
+	}
+
+	timings := make([]float64, samples)
+	for i, op := range ops {
+		start := time.Now()
+		op()
+		timings[i] = float64(time.Since(start))
+	}
+
+	sorted := append([]float64(nil), timings...)
+	sort.Float64s(sorted)
+	var maxT float64
+	for _, percentile := range []float64{1, 0.99, 0.9, 0.5} {
+		cutoff := sorted[int(percentile*float64(samples-1))]
+		var w welch
+		for i, x := range timings {
+			if x <= cutoff {
+				w.push(int(classes[i]&1), x)
+			}
+		}
+		if w.n[0] < 2 || w.n[1] < 2 {
+			// The cropping removed a whole class, which is itself a
+			// sign of input-dependent timings, and already detected at
+			// the higher percentiles.
+			continue
+		}
+		tt := w.t()
+		t.Logf("percentile %v: mean %.0fns vs %.0fns, t = %.2f", percentile, w.mean[0], w.mean[1], tt)
+		if math.Abs(tt) > maxT {
+			maxT = math.Abs(tt)
+		}
+	}
+	if maxT > dudectThreshold {
+		t.Errorf("timings depend on the input class: |t| = %.2f > %d", maxT, dudectThreshold)
+	}
+}
+
+func TestDudectTableSelect(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tab table
+	tab[0] = NewPoint().Set(q)
+	for i := 1; i < 15; i++ {
+		tab[i] = NewPoint().Add(tab[i-1], q)
+	}
+
+	// Each sample runs Select a few times to stay well above the timer
+	// resolution.
+	p := NewPoint()
+	dudect(t, func(class int) func() {
+		var n [16]uint8
+		if class == 1 {
+			rand.Read(n[:])
+			for i := range n {
+				n[i] &= 15
+			}
+		}
+		return func() {
+			for _, n := range n {
+				tab.Select(p, n)
+			}
+		}
+	})
+}
+
+func TestDudectScalarMult(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The fixed class is a mostly-zero scalar, which hits the identity point
+	// in almost every addition of the double-and-add loop.
+	fixed := make([]byte, ElementLength)
+	fixed[ElementLength-1] = 1
+	p := NewPoint()
+	dudect(t, func(class int) func() {
+		k := fixed
+		if class == 1 {
+			k = randomScalar(t)
+		}
+		return func() {
+			p.ScalarMult(q, k)
+		}
+	})
+}
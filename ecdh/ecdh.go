@@ -47,6 +47,9 @@ type Curve interface {
 	// Version 2.0, Section 2.3.4. Compressed encodings and the point at
 	// infinity are rejected.
 	//
+	// For secp256k1, compressed encodings are also accepted, and the key is
+	// stored and returned by PublicKey.Bytes in uncompressed form.
+	//
 	// For X25519, this only checks the u-coordinate length. Adversarially
 	// selected public keys can cause ECDH to return an error.
 	NewPublicKey(key []byte) (*PublicKey, error)
@@ -61,6 +64,10 @@ type Curve interface {
 	// The private method also allow us to expand the ECDH interface with more
 	// methods in the future without breaking backwards compatibility.
 	privateKeyToPublicKey(*PrivateKey) *PublicKey
+
+	// compressPublicKey returns the compressed encoding of a PublicKey. It's
+	// exposed as the PublicKey.BytesCompressed method.
+	compressPublicKey(*PublicKey) []byte
 }
 
 // PublicKey is an ECDH public key, usually a peer's ECDH share sent over the wire.
@@ -77,6 +84,13 @@ func (k *PublicKey) Bytes() []byte {
 	return append(buf[:0], k.publicKey...)
 }
 
+// BytesCompressed returns the compressed encoding of the public key, as
+// specified in SEC 1, Version 2.0, Section 2.3.3. NewPublicKey accepts it and
+// returns an equal PublicKey.
+func (k *PublicKey) BytesCompressed() []byte {
+	return k.curve.compressPublicKey(k)
+}
+
 // Equal returns whether x represents the same public key as k.
 //
 // Note that there can be equivalent public keys with different encodings which
@@ -107,6 +121,17 @@ type PrivateKey struct {
 	publicKeyOnce sync.Once
 }
 
+// ECDH performs a ECDH exchange and returns the shared secret. The PrivateKey
+// and PublicKey must use the same curve.
+//
+// See Curve.ECDH for the format of the shared secret.
+func (k *PrivateKey) ECDH(remote *PublicKey) ([]byte, error) {
+	if k.curve != remote.curve {
+		return nil, errCurveMismatch
+	}
+	return k.curve.ECDH(k, remote)
+}
+
 // Bytes returns a copy of the encoding of the private key.
 func (k *PrivateKey) Bytes() []byte {
 	// Copy the private key to a fixed size buffer that can get allocated on the
@@ -115,6 +140,18 @@ func (k *PrivateKey) Bytes() []byte {
 	return append(buf[:0], k.privateKey...)
 }
 
+// Wipe overwrites the private key with zeros. The key must not be used
+// afterwards.
+//
+// Wipe is best-effort: the garbage collector may have moved or copied the key
+// material, and copies returned by Bytes or held by the caller are not
+// affected.
+func (k *PrivateKey) Wipe() {
+	for i := range k.privateKey {
+		k.privateKey[i] = 0
+	}
+}
+
 // Equal returns whether x represents the same private key as k.
 //
 // Note that there can be equivalent private keys with different encodings which
@@ -0,0 +1,172 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdh_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+	"github.com/wdvxdr1123/secp256k1/ecdh"
+)
+
+func generateKey(t *testing.T) *ecdh.PrivateKey {
+	t.Helper()
+	k, err := ecdh.S256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestECDHPoint(t *testing.T) {
+	curve := ecdh.S256().(*ecdh.SecCurve[*secp256k1.Point])
+	alice, bob := generateKey(t), generateKey(t)
+
+	aliceShared, err := curve.ECDHPoint(alice, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobShared, err := curve.ECDHPoint(bob, alice.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(aliceShared, bobShared) {
+		t.Fatalf("shared points differ: %x, %x", aliceShared, bobShared)
+	}
+	if len(aliceShared) != 33 {
+		t.Errorf("shared point is %d bytes, want 33", len(aliceShared))
+	}
+
+	// The compressed point decompresses to [a·b]G, and its x-coordinate is
+	// the ECDH output.
+	p := mustPoint(t, aliceShared)
+	want, err := secp256k1.NewPoint().ScalarMult(mustPoint(t, bob.PublicKey().Bytes()), alice.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Equal(want) != 1 {
+		t.Errorf("shared point does not match [a]B")
+	}
+	x, err := curve.ECDH(alice, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(x, aliceShared[1:]) {
+		t.Errorf("ECDH output %x is not the x-coordinate of %x", x, aliceShared)
+	}
+}
+
+func mustPoint(t *testing.T, b []byte) *secp256k1.Point {
+	t.Helper()
+	p, err := secp256k1.NewPoint().SetBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNewPublicKeyCompressed(t *testing.T) {
+	alice, bob := generateKey(t), generateKey(t)
+	compressed := mustPoint(t, bob.PublicKey().Bytes()).BytesCompressed()
+	bobCompressed, err := ecdh.S256().NewPublicKey(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bobCompressed.Bytes(), bob.PublicKey().Bytes()) {
+		t.Errorf("compressed key was not normalized to uncompressed form")
+	}
+	if !bobCompressed.Equal(bob.PublicKey()) {
+		t.Errorf("compressed and uncompressed keys are not equal")
+	}
+
+	fromCompressed, err := alice.Curve().ECDH(alice, bobCompressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromUncompressed, err := alice.Curve().ECDH(alice, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromCompressed, fromUncompressed) {
+		t.Errorf("shared secrets differ: %x, %x", fromCompressed, fromUncompressed)
+	}
+
+	hybrid := bob.PublicKey().Bytes()
+	hybrid[0] = 6 | hybrid[len(hybrid)-1]&1
+	for _, key := range [][]byte{nil, {0}, hybrid, compressed[:32]} {
+		if _, err := ecdh.S256().NewPublicKey(key); err == nil {
+			t.Errorf("NewPublicKey(%x) succeeded", key)
+		}
+	}
+}
+
+func TestPublicKeyBytesCompressed(t *testing.T) {
+	k := generateKey(t)
+	pub := k.PublicKey()
+	compressed := pub.BytesCompressed()
+	if len(compressed) != 33 || (compressed[0] != 2 && compressed[0] != 3) {
+		t.Fatalf("invalid compressed encoding %x", compressed)
+	}
+	if !bytes.Equal(compressed[1:], pub.Bytes()[1:33]) {
+		t.Errorf("compressed encoding %x does not carry the x-coordinate", compressed)
+	}
+	roundTrip, err := ecdh.S256().NewPublicKey(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roundTrip.Equal(pub) {
+		t.Errorf("compressed public key did not round-trip")
+	}
+
+	// Bytes returns a copy.
+	b := pub.Bytes()
+	b[1] ^= 0xff
+	if bytes.Equal(b, pub.Bytes()) {
+		t.Errorf("Bytes returned the internal buffer")
+	}
+}
+
+func TestNewPrivateKeyRange(t *testing.T) {
+	nMinusOne := []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe,
+		0xba, 0xae, 0xdc, 0xe6, 0xaf, 0x48, 0xa0, 0x3b, 0xbf, 0xd2, 0x5e, 0x8c, 0xd0, 0x36, 0x41, 0x40,
+	}
+	if _, err := ecdh.S256().NewPrivateKey(nMinusOne); err != nil {
+		t.Errorf("n - 1: %v", err)
+	}
+	n := append(nMinusOne[:31:31], 0x41)
+	nPlusOne := append(nMinusOne[:31:31], 0x42)
+	// Values between n and the old, incorrect bound were accepted before.
+	aboveN := append(append([]byte{}, nMinusOne[:16]...), bytes.Repeat([]byte{0xff}, 16)...)
+	for _, key := range [][]byte{make([]byte, 32), n, nPlusOne, aboveN} {
+		if _, err := ecdh.S256().NewPrivateKey(key); err == nil {
+			t.Errorf("%x: expected error", key)
+		}
+	}
+}
+
+func TestNewPublicKeyErrors(t *testing.T) {
+	curve := ecdh.S256()
+	if _, err := curve.NewPublicKey([]byte{0}); !errors.Is(err, secp256k1.ErrInfinityNotAllowed) {
+		t.Errorf("infinity: got %v, want ErrInfinityNotAllowed", err)
+	}
+	if _, err := curve.NewPublicKey(nil); !errors.Is(err, secp256k1.ErrInvalidLength) {
+		t.Errorf("empty: got %v, want ErrInvalidLength", err)
+	}
+	g := secp256k1.NewGenerator().Bytes()
+	if _, err := curve.NewPublicKey(g[:64]); !errors.Is(err, secp256k1.ErrInvalidLength) {
+		t.Errorf("truncated: got %v, want ErrInvalidLength", err)
+	}
+	if _, err := curve.NewPublicKey(append(g[:64:64], g[64]^1)); !errors.Is(err, secp256k1.ErrNotOnCurve) {
+		t.Errorf("off curve: got %v, want ErrNotOnCurve", err)
+	}
+	p := secp256k1.P()
+	if _, err := curve.NewPublicKey(append([]byte{2}, p...)); !errors.Is(err, secp256k1.ErrNonCanonical) {
+		t.Errorf("X = p: got %v, want ErrNonCanonical", err)
+	}
+}
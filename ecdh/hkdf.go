@@ -0,0 +1,37 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdh
+
+import (
+	"crypto/hmac"
+	"errors"
+	"hash"
+)
+
+// hkdf derives length bytes from secret with HKDF, as specified in RFC 5869,
+// using the HMAC of h, an empty salt, and info.
+func hkdf(h func() hash.Hash, secret, info []byte, length int) ([]byte, error) {
+	// HKDF-Extract. An empty salt is the same as HashLen zero bytes.
+	extractor := hmac.New(h, nil)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+
+	// HKDF-Expand.
+	if length < 0 || length > 255*len(prk) {
+		return nil, errors.New("crypto/ecdh: invalid KDF output length")
+	}
+	expander := hmac.New(h, prk)
+	out := make([]byte, 0, length+len(prk))
+	var t []byte
+	for counter := byte(1); len(out) < length; counter++ {
+		expander.Reset()
+		expander.Write(t)
+		expander.Write(info)
+		expander.Write([]byte{counter})
+		t = expander.Sum(t[:0])
+		out = append(out, t...)
+	}
+	return out[:length], nil
+}
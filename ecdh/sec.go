@@ -7,6 +7,8 @@ package ecdh
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash"
 	"io"
 	"math/bits"
 
@@ -23,6 +25,7 @@ type SecCurve[T Point[T]] struct {
 type Point[T any] interface {
 	Bytes() []byte
 	BytesX() ([]byte, error)
+	BytesCompressed() []byte
 	SetBytes([]byte) (T, error)
 	ScalarMult(T, []byte) (T, error)
 	ScalarBaseMult([]byte) (T, error)
@@ -34,6 +37,15 @@ func (c *SecCurve[Point]) String() string {
 
 var errInvalidPrivateKey = errors.New("crypto/ecdh: invalid private key")
 
+var errCurveMismatch = errors.New("crypto/ecdh: private key and public key curves do not match")
+
+// GenerateKey generates a random PrivateKey by reading len(scalarOrder) bytes
+// at a time from rand, and rejecting candidates that are zero or not less
+// than the order. The returned key is exactly the first accepted candidate
+// read from rand.
+//
+// A reader that never produces a valid scalar, such as one that only returns
+// zeroes, will make GenerateKey loop forever.
 func (c *SecCurve[Point]) GenerateKey(rand io.Reader) (*PrivateKey, error) {
 	key := make([]byte, len(c.scalarOrder))
 	for {
@@ -41,11 +53,6 @@ func (c *SecCurve[Point]) GenerateKey(rand io.Reader) (*PrivateKey, error) {
 			return nil, err
 		}
 
-		// In tests, rand will return all zeros and NewPrivateKey will reject
-		// the zero key as it generates the identity as a public key. This also
-		// makes this function consistent with crypto/elliptic.GenerateKey.
-		key[1] ^= 0x42
-
 		k, err := c.NewPrivateKey(key)
 		if err == errInvalidPrivateKey {
 			continue
@@ -90,6 +97,19 @@ func (c *SecCurve[Point]) privateKeyToPublicKey(key *PrivateKey) *PublicKey {
 	}
 }
 
+func (c *SecCurve[Point]) compressPublicKey(key *PublicKey) []byte {
+	if key.curve != c {
+		panic("crypto/ecdh: internal error: converting the wrong key type")
+	}
+	p, err := c.newPoint().SetBytes(key.publicKey)
+	if err != nil {
+		// This is unreachable because NewPublicKey and privateKeyToPublicKey
+		// only produce valid encodings.
+		panic("crypto/ecdh: internal error: invalid stored public key")
+	}
+	return p.BytesCompressed()
+}
+
 // isZero returns whether a is all zeroes in constant time.
 func isZero(a []byte) bool {
 	var acc byte
@@ -129,18 +149,26 @@ func isLess(a, b []byte) bool {
 }
 
 func (c *SecCurve[Point]) NewPublicKey(key []byte) (*PublicKey, error) {
-	// Reject the point at infinity and compressed encodings.
-	if len(key) == 0 || key[0] != 4 {
+	// Reject the point at infinity and hybrid encodings.
+	switch {
+	case len(key) == 0:
+		return nil, fmt.Errorf("crypto/ecdh: invalid public key: %w", secp256k1.ErrInvalidLength)
+	case len(key) == 1 && key[0] == 0:
+		return nil, fmt.Errorf("crypto/ecdh: invalid public key: %w", secp256k1.ErrInfinityNotAllowed)
+	case key[0] != 2 && key[0] != 3 && key[0] != 4:
 		return nil, errors.New("crypto/ecdh: invalid public key")
 	}
 	// SetBytes also checks that the point is on the SecCurve.
-	if _, err := c.newPoint().SetBytes(key); err != nil {
+	p, err := c.newPoint().SetBytes(key)
+	if err != nil {
 		return nil, err
 	}
 
+	// Store compressed keys in uncompressed form, so that equivalent keys have
+	// the same encoding.
 	return &PublicKey{
 		curve:     c,
-		publicKey: append([]byte{}, key...),
+		publicKey: p.Bytes(),
 	}, nil
 }
 
@@ -156,6 +184,49 @@ func (c *SecCurve[Point]) ECDH(local *PrivateKey, remote *PublicKey) ([]byte, er
 	return p.BytesX()
 }
 
+// ECDHPoint is like ECDH, but returns the whole shared point in compressed
+// form, as specified in SEC 1, Version 2.0, Section 2.3.3, rather than just
+// its x-coordinate. The 33-byte output also commits to the parity of the
+// y-coordinate, which some protocols, such as libsecp256k1's hashed ECDH,
+// feed into a key derivation function.
+//
+// The PrivateKey and PublicKey must use the curve c. If the result is the
+// point at infinity, ECDHPoint returns an error.
+func (c *SecCurve[Point]) ECDHPoint(local *PrivateKey, remote *PublicKey) ([]byte, error) {
+	if local.curve != c || remote.curve != c {
+		return nil, errCurveMismatch
+	}
+	p, err := c.newPoint().SetBytes(remote.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ScalarMult(p, local.privateKey); err != nil {
+		return nil, err
+	}
+	out := p.BytesCompressed()
+	if len(out) == 1 {
+		return nil, errors.New("crypto/ecdh: ECDH result is the point at infinity")
+	}
+	return out, nil
+}
+
+// ECDHWithKDF performs an ECDH exchange like ECDH, and derives outLen bytes
+// from the shared x-coordinate with HKDF (RFC 5869), using the HMAC of h, an
+// empty salt, and info, which should bind the output to the protocol and its
+// context. outLen can be at most 255 times the size of h.
+//
+// The PrivateKey and PublicKey must use the curve c.
+func (c *SecCurve[Point]) ECDHWithKDF(local *PrivateKey, remote *PublicKey, info []byte, outLen int, h func() hash.Hash) ([]byte, error) {
+	if local.curve != c || remote.curve != c {
+		return nil, errCurveMismatch
+	}
+	shared, err := c.ECDH(local, remote)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf(h, shared, info, outLen)
+}
+
 // S256 returns a SecCurve which implements fiat.
 //
 // Multiple invocations of this function will return the same value, so it can
@@ -168,4 +239,4 @@ var s256 = &SecCurve[*secp256k1.Point]{
 	scalarOrder: s256Order,
 }
 
-var s256Order = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xBA, 0xAE, 0xDC, 0xE6, 0xAF, 0x48, 0xA0, 0x3B, 0xBF, 0xD2, 0x5E, 0x8C, 0xD0, 0x36, 0x41, 0x41}
+var s256Order = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE, 0xBA, 0xAE, 0xDC, 0xE6, 0xAF, 0x48, 0xA0, 0x3B, 0xBF, 0xD2, 0x5E, 0x8C, 0xD0, 0x36, 0x41, 0x41}
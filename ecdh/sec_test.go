@@ -0,0 +1,194 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func TestPrivateKeyECDH(t *testing.T) {
+	alice, err := S256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := S256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := alice.ECDH(bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := S256().ECDH(alice, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("PrivateKey.ECDH = %x, want %x", got, want)
+	}
+	if other, _ := bob.ECDH(alice.PublicKey()); !bytes.Equal(got, other) {
+		t.Errorf("shared secrets differ: %x, %x", got, other)
+	}
+}
+
+func TestCrossCurve(t *testing.T) {
+	// A second curve instance with the same arithmetic is still a different
+	// curve, and keys must not mix across curves.
+	other := &SecCurve[*secp256k1.Point]{
+		name:        "other",
+		newPoint:    secp256k1.NewPoint,
+		scalarOrder: s256Order,
+	}
+	key, err := S256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := other.NewPrivateKey(key.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, err := other.NewPublicKey(key.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Equal(otherKey) || otherKey.Equal(key) {
+		t.Error("private keys on different curves are equal")
+	}
+	if key.PublicKey().Equal(otherPub) || otherPub.Equal(key.PublicKey()) {
+		t.Error("public keys on different curves are equal")
+	}
+	if !otherKey.Equal(otherKey) || !otherPub.Equal(otherKey.PublicKey()) {
+		t.Error("keys on the same curve are not equal")
+	}
+	if _, err := key.ECDH(otherPub); err != errCurveMismatch {
+		t.Errorf("ECDH across curves: got %v, want %v", err, errCurveMismatch)
+	}
+	curve := S256().(*SecCurve[*secp256k1.Point])
+	if _, err := curve.ECDHPoint(key, otherPub); err != errCurveMismatch {
+		t.Errorf("ECDHPoint across curves: got %v, want %v", err, errCurveMismatch)
+	}
+	if _, err := other.ECDHPoint(otherKey, key.PublicKey()); err != errCurveMismatch {
+		t.Errorf("ECDHPoint with a foreign public key: got %v, want %v", err, errCurveMismatch)
+	}
+	if _, err := curve.ECDHWithKDF(key, otherPub, nil, 32, sha256.New); err != errCurveMismatch {
+		t.Errorf("ECDHWithKDF across curves: got %v, want %v", err, errCurveMismatch)
+	}
+}
+
+func TestPrivateKeyWipe(t *testing.T) {
+	key, err := S256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := key.privateKey
+	key.Wipe()
+	for _, b := range buf {
+		if b != 0 {
+			t.Fatalf("private key not wiped: %x", buf)
+		}
+	}
+}
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869, Appendix A.3: SHA-256 with a zero-length salt and info.
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	want, _ := hex.DecodeString("8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d" +
+		"9d201395faa4b61a96c8")
+	got, err := hkdf(sha256.New, ikm, nil, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("hkdf = %x, want %x", got, want)
+	}
+	if _, err := hkdf(sha256.New, ikm, nil, 255*32+1); err == nil {
+		t.Error("expected error for overlong output")
+	}
+}
+
+func TestECDHWithKDF(t *testing.T) {
+	curve := S256().(*SecCurve[*secp256k1.Point])
+	alice, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := []byte("test protocol v1")
+	a, err := curve.ECDHWithKDF(alice, bob.PublicKey(), info, 64, sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := curve.ECDHWithKDF(bob, alice.PublicKey(), info, 64, sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 64 || !bytes.Equal(a, b) {
+		t.Errorf("derived keys differ: %x, %x", a, b)
+	}
+
+	shared, err := curve.ECDH(alice, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := hkdf(sha256.New, shared, info, 64); !bytes.Equal(a, want) {
+		t.Errorf("ECDHWithKDF = %x, want HKDF of the shared secret %x", a, want)
+	}
+	if bytes.Contains(a, shared) {
+		t.Error("output contains the raw shared secret")
+	}
+
+	other, err := curve.ECDHWithKDF(alice, bob.PublicKey(), []byte("test protocol v2"), 64, sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, other) {
+		t.Error("different info produced the same key")
+	}
+	if short, _ := curve.ECDHWithKDF(alice, bob.PublicKey(), info, 16, sha256.New); !bytes.Equal(short, a[:16]) {
+		t.Errorf("16-byte output %x is not a prefix of the 64-byte one", short)
+	}
+	if _, err := curve.ECDHWithKDF(alice, bob.PublicKey(), info, -1, sha256.New); err == nil {
+		t.Error("expected error for negative length")
+	}
+}
+
+func TestGenerateKeyRejectionSampling(t *testing.T) {
+	valid := make([]byte, 32)
+	for i := range valid {
+		valid[i] = byte(i + 1)
+	}
+	// A zero candidate and an all-ones candidate (which is larger than the
+	// order) must both be rejected, and the next candidate used unchanged.
+	var stream []byte
+	stream = append(stream, make([]byte, 32)...)
+	stream = append(stream, bytes.Repeat([]byte{0xff}, 32)...)
+	stream = append(stream, valid...)
+	r := bytes.NewReader(stream)
+
+	key, err := S256().GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.Bytes(); !bytes.Equal(got, valid) {
+		t.Errorf("GenerateKey = %x, want %x", got, valid)
+	}
+	if r.Len() != 0 {
+		t.Errorf("GenerateKey left %d unread bytes", r.Len())
+	}
+
+	if _, err := S256().GenerateKey(bytes.NewReader(make([]byte, 31))); err == nil {
+		t.Error("expected error from a short reader")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// CompactSignatureLength is the length of the r ‖ s ‖ v signature encoding
+// used by EncodeCompact and ParseCompact.
+const CompactSignatureLength = 2*secp256k1.ElementLength + 1
+
+// EncodeCompact encodes the signature (r, s) and its recovery ID recid, as
+// used by RecoverPublicKey, as the 65-byte r ‖ s ‖ v encoding of Ethereum. r
+// and s are big-endian values of up to 32 bytes, which are zero-extended, and
+// v is recid itself, in [0, 3]. Callers that need the legacy v of 27 or 28
+// can add 27 to the last byte.
+func EncodeCompact(r, s []byte, recid int) ([CompactSignatureLength]byte, error) {
+	var sig [CompactSignatureLength]byte
+	if len(r) > secp256k1.ElementLength || len(s) > secp256k1.ElementLength {
+		return sig, errors.New("ecdsa: invalid signature value length")
+	}
+	if recid < 0 || recid > 3 {
+		return sig, errors.New("ecdsa: invalid recovery ID")
+	}
+	copy(sig[secp256k1.ElementLength-len(r):], r)
+	copy(sig[2*secp256k1.ElementLength-len(s):], s)
+	sig[2*secp256k1.ElementLength] = byte(recid)
+	return sig, nil
+}
+
+// ParseCompact decodes a 65-byte r ‖ s ‖ v signature, as produced by
+// EncodeCompact, into 32-byte r and s values and the recovery ID. v may be
+// the recovery ID itself, in [0, 3], or the recovery ID plus 27. The range of
+// r and s is not checked, which is left to Verify and RecoverPublicKey.
+func ParseCompact(sig []byte) (r, s []byte, recid int, err error) {
+	if len(sig) != CompactSignatureLength {
+		return nil, nil, 0, errors.New("ecdsa: invalid compact signature length")
+	}
+	v := int(sig[2*secp256k1.ElementLength])
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 3 {
+		return nil, nil, 0, errors.New("ecdsa: invalid recovery ID")
+	}
+	r = append([]byte(nil), sig[:secp256k1.ElementLength]...)
+	s = append([]byte(nil), sig[secp256k1.ElementLength:2*secp256k1.ElementLength]...)
+	return r, s, v, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func TestCompactSignature(t *testing.T) {
+	priv, pub := generateKey(t)
+	hash := sha256.Sum256([]byte("testing"))
+	for i := 0; i < 16; i++ {
+		r, s, recid, err := sign(priv, hash[:], &Opts{LowS: true}, func() (*secp256k1.Scalar, error) {
+			return randomScalar(rand.Reader)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := EncodeCompact(r.Bytes(), s.Bytes(), int(recid))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig[:32], r.Bytes()) || !bytes.Equal(sig[32:64], s.Bytes()) || sig[64] != recid {
+			t.Errorf("EncodeCompact = %x", sig)
+		}
+
+		// The legacy v = recid + 27 is accepted too.
+		legacy := sig
+		legacy[64] += 27
+		for _, enc := range [][]byte{sig[:], legacy[:]} {
+			r2, s2, recid2, err := ParseCompact(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(r2, r.Bytes()) || !bytes.Equal(s2, s.Bytes()) || recid2 != int(recid) {
+				t.Errorf("ParseCompact(%x) = %x, %x, %d", enc, r2, s2, recid2)
+			}
+			Q, err := RecoverPublicKey(hash[:], r2, s2, recid2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(Q.Bytes(), pub) {
+				t.Errorf("recovered %x, want %x", Q.Bytes(), pub)
+			}
+		}
+	}
+
+	// Short values are zero-extended.
+	sig, err := EncodeCompact([]byte{1}, []byte{2, 3}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, CompactSignatureLength)
+	want[31], want[62], want[63], want[64] = 1, 2, 3, 1
+	if !bytes.Equal(sig[:], want) {
+		t.Errorf("EncodeCompact = %x, want %x", sig, want)
+	}
+}
+
+func TestCompactSignatureInvalid(t *testing.T) {
+	r := make([]byte, 32)
+	if _, err := EncodeCompact(make([]byte, 33), r, 0); err == nil {
+		t.Error("EncodeCompact accepted a 33-byte r")
+	}
+	if _, err := EncodeCompact(r, make([]byte, 33), 0); err == nil {
+		t.Error("EncodeCompact accepted a 33-byte s")
+	}
+	for _, recid := range []int{-1, 4, 27} {
+		if _, err := EncodeCompact(r, r, recid); err == nil {
+			t.Errorf("EncodeCompact accepted recovery ID %d", recid)
+		}
+	}
+
+	for _, n := range []int{0, 64, 66} {
+		if _, _, _, err := ParseCompact(make([]byte, n)); err == nil {
+			t.Errorf("ParseCompact accepted a %d-byte signature", n)
+		}
+	}
+	for _, v := range []byte{4, 26, 31, 255} {
+		sig := make([]byte, CompactSignatureLength)
+		sig[64] = v
+		if _, _, _, err := ParseCompact(sig); err == nil {
+			t.Errorf("ParseCompact accepted v = %d", v)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+var errInvalidDER = errors.New("ecdsa: invalid DER signature encoding")
+
+// ParseDERSignature decodes a signature encoded as the ASN.1 DER
+// SEQUENCE { INTEGER r, INTEGER s }, and returns r and s as 32-byte
+// big-endian values, as accepted by Verify.
+//
+// Decoding is strict, following the signature encoding rules of BIP 66: the
+// lengths must be in their short form and match the input exactly, and the
+// integers must be positive and minimally encoded, with a leading zero byte
+// only where the next byte would otherwise make them negative. Integers that
+// don't fit in 32 bytes are also rejected. The range of r and s is not
+// checked, which is left to Verify. Unlike in BIP 66, der must not be followed
+// by a sighash byte.
+func ParseDERSignature(der []byte) (r, s []byte, err error) {
+	// The shortest signature has two one-byte integers, and the longest two
+	// 33-byte ones, each with their two header bytes.
+	if len(der) < 8 || len(der) > 72 {
+		return nil, nil, errInvalidDER
+	}
+	if der[0] != 0x30 || int(der[1]) != len(der)-2 {
+		return nil, nil, errInvalidDER
+	}
+	r, rest, err := parseDERInteger(der[2:])
+	if err != nil {
+		return nil, nil, err
+	}
+	s, rest, err = parseDERInteger(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, errInvalidDER
+	}
+	return r, s, nil
+}
+
+// parseDERInteger decodes a minimally encoded, positive DER INTEGER of up to
+// 32 bytes from the start of b, and returns it zero-extended to 32 bytes along
+// with the rest of b.
+func parseDERInteger(b []byte) (v, rest []byte, err error) {
+	if len(b) < 2 || b[0] != 0x02 {
+		return nil, nil, errInvalidDER
+	}
+	n := int(b[1])
+	if n == 0 || n > len(b)-2 {
+		return nil, nil, errInvalidDER
+	}
+	v, rest = b[2:2+n], b[2+n:]
+	if v[0]&0x80 != 0 {
+		// Negative.
+		return nil, nil, errInvalidDER
+	}
+	if len(v) > 1 && v[0] == 0 && v[1]&0x80 == 0 {
+		// Not minimally encoded.
+		return nil, nil, errInvalidDER
+	}
+	if len(v) > 1 && v[0] == 0 {
+		v = v[1:]
+	}
+	if len(v) > secp256k1.ElementLength {
+		return nil, nil, errInvalidDER
+	}
+	out := make([]byte, secp256k1.ElementLength)
+	copy(out[secp256k1.ElementLength-len(v):], v)
+	return out, rest, nil
+}
+
+// EncodeDERSignature encodes the big-endian signature values r and s, of up to
+// 32 bytes each, as the ASN.1 DER SEQUENCE { INTEGER r, INTEGER s }, with the
+// minimal encoding accepted by ParseDERSignature. It panics if r or s is
+// longer than 32 bytes after removing leading zeroes.
+func EncodeDERSignature(r, s []byte) []byte {
+	out := make([]byte, 2, 72)
+	out[0] = 0x30
+	out = appendDERInteger(out, r)
+	out = appendDERInteger(out, s)
+	out[1] = byte(len(out) - 2)
+	return out
+}
+
+// appendDERInteger appends the DER INTEGER encoding of the non-negative
+// big-endian value v to b.
+func appendDERInteger(b, v []byte) []byte {
+	for len(v) > 1 && v[0] == 0 {
+		v = v[1:]
+	}
+	if len(v) > secp256k1.ElementLength {
+		panic("ecdsa: signature value too long for DER encoding")
+	}
+	if len(v) == 0 {
+		v = []byte{0}
+	}
+	if v[0]&0x80 != 0 {
+		b = append(b, 0x02, byte(len(v)+1), 0)
+	} else {
+		b = append(b, 0x02, byte(len(v)))
+	}
+	return append(b, v...)
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+// derSig is the signature of the first Bitcoin transaction between two
+// people, in block 170, without its sighash byte.
+const derSig = "304402204e45e16932b8af514961a1d3a1a25fdf3f4f7732e9d624c6c61548ab5fb8cd41" +
+	"0220181522ec8eca07de4860a4acdd12909d831cc56cbbac4622082221a8768d1d09"
+
+// TestParseDERSignatureBIP66 checks each of the rules of IsValidSignatureEncoding
+// from BIP 66, with one invalid encoding per rule.
+func TestParseDERSignatureBIP66(t *testing.T) {
+	valid := []struct {
+		name, der, r, s string
+	}{
+		{"block 170", derSig,
+			"4e45e16932b8af514961a1d3a1a25fdf3f4f7732e9d624c6c61548ab5fb8cd41",
+			"181522ec8eca07de4860a4acdd12909d831cc56cbbac4622082221a8768d1d09"},
+		{"shortest", "3006020101020101",
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"0000000000000000000000000000000000000000000000000000000000000001"},
+		{"zero", "3006020100020100",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000000"},
+		{"padded high bit", "30080202008002020080",
+			"0000000000000000000000000000000000000000000000000000000000000080",
+			"0000000000000000000000000000000000000000000000000000000000000080"},
+		{"longest", "3046022100" + repeatHex("ff", 32) + "022100" + repeatHex("80", 32),
+			repeatHex("ff", 32), repeatHex("80", 32)},
+	}
+	for _, tt := range valid {
+		r, s, err := ParseDERSignature(decodeHex(t, tt.der))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !bytes.Equal(r, decodeHex(t, tt.r)) || !bytes.Equal(s, decodeHex(t, tt.s)) {
+			t.Errorf("%s: got r = %x, s = %x", tt.name, r, s)
+		}
+		if got := EncodeDERSignature(r, s); !bytes.Equal(got, decodeHex(t, tt.der)) {
+			t.Errorf("%s: re-encoded as %x", tt.name, got)
+		}
+	}
+
+	invalid := []struct {
+		name, der string
+	}{
+		{"empty", ""},
+		{"too short", "30050201010201"},
+		{"too long", "3047022100" + repeatHex("ff", 33) + "022100" + repeatHex("80", 32)},
+		{"trailing sighash byte", derSig + "01"},
+		{"wrong sequence tag", "3106020101020101"},
+		{"wrong sequence length", "3007020101020101"},
+		{"long form sequence length", "308106020101020101"},
+		{"missing S", "30220220" + repeatHex("01", 32)},
+		{"R length past the end", "3006020501020101"},
+		{"S length past the end", "3006020101020201"},
+		{"R not an integer", "3006030101020101"},
+		{"S not an integer", "3006020101030101"},
+		{"zero-length R", "30060200020101" + "01"},
+		{"zero-length S", "3006020101020001"},
+		{"negative R", "3006020181020101"},
+		{"negative S", "3006020101020181"},
+		{"extra zero byte in R", "300702020001020101"},
+		{"extra zero byte in S", "300702010102020001"},
+		{"R longer than 32 bytes", "3027022201" + repeatHex("00", 33) + "020101"},
+		{"garbage after S", "3008020101020101" + "0000"},
+	}
+	for _, tt := range invalid {
+		if r, s, err := ParseDERSignature(decodeHex(t, tt.der)); err == nil {
+			t.Errorf("%s: accepted %s as r = %x, s = %x", tt.name, tt.der, r, s)
+		}
+	}
+}
+
+func repeatHex(s string, n int) string {
+	return string(bytes.Repeat([]byte(s), n))
+}
+
+func TestDERSignatureRoundTrip(t *testing.T) {
+	priv, pub := generateKey(t)
+	for i := 0; i < 16; i++ {
+		hash := make([]byte, sha256.Size)
+		rand.Read(hash)
+		r, s, err := Sign(priv, hash, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der := EncodeDERSignature(r, s)
+		r2, s2, err := ParseDERSignature(der)
+		if err != nil {
+			t.Fatalf("%x: %v", der, err)
+		}
+		if !bytes.Equal(r, r2) || !bytes.Equal(s, s2) {
+			t.Errorf("%x: got r = %x, s = %x, want r = %x, s = %x", der, r2, s2, r, s)
+		}
+		if !Verify(pub, hash, r2, s2) {
+			t.Errorf("%x: signature doesn't verify", der)
+		}
+	}
+}
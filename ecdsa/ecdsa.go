@@ -0,0 +1,259 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ecdsa implements the Elliptic Curve Digital Signature Algorithm over
+// secp256k1, as defined in SEC 1, Version 2.0, Section 4.1.
+//
+// Private keys are 32-byte big-endian scalars, public keys are SEC 1 encoded
+// points, and the signature values r and s are 32-byte big-endian scalars.
+package ecdsa
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// Opts holds options for signing and verification.
+type Opts struct {
+	// LowS, when signing, normalizes s to the lower half of the range [1, n),
+	// and when verifying, rejects signatures with s in the upper half. This
+	// removes the malleability of (r, s) into (r, n - s), as required for
+	// example by Bitcoin's standardness rules.
+	LowS bool
+}
+
+// Sign signs hash (which should be the result of hashing a larger message)
+// using the private key priv and randomness from rand. If the hash is longer
+// than 256 bits, it is truncated to its leftmost 256 bits.
+func Sign(priv, hash []byte, rand io.Reader) (r, s []byte, err error) {
+	return SignWithOpts(priv, hash, rand, nil)
+}
+
+// SignWithOpts is like Sign, but accepts signing options. A nil opts is
+// equivalent to the zero value.
+func SignWithOpts(priv, hash []byte, rand io.Reader, opts *Opts) (r, s []byte, err error) {
+	rs, ss, _, err := sign(priv, hash, opts, func() (*secp256k1.Scalar, error) {
+		return randomScalar(rand)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rs.Bytes(), ss.Bytes(), nil
+}
+
+// SignWithNonce signs hash using the private key priv, like Sign, but uses
+// the caller-supplied nonce k instead of drawing one, for example from a
+// hardware device or for reproducible tests.
+//
+// k must be secret, uniformly random or derived as in RFC 6979, and never
+// reused with a different hash: a repeated or predictable nonce reveals the
+// private key. If k is zero, or it produces r = 0 or s = 0, SignWithNonce
+// returns an error, and the caller should retry with a different nonce.
+func SignWithNonce(priv, hash []byte, k *secp256k1.Scalar) (r, s []byte, err error) {
+	if k.IsZero() == 1 {
+		return nil, nil, errors.New("ecdsa: invalid nonce")
+	}
+	used := false
+	rs, ss, _, err := sign(priv, hash, nil, func() (*secp256k1.Scalar, error) {
+		if used {
+			return nil, errors.New("ecdsa: nonce produced an invalid signature")
+		}
+		used = true
+		// sign inverts the nonce in place, so pass it a copy.
+		return new(secp256k1.Scalar).Set(k), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rs.Bytes(), ss.Bytes(), nil
+}
+
+// sign implements ECDSA signing, drawing nonces from nextNonce until one
+// produces a valid signature. It also returns the recovery ID of the
+// signature: bit zero is the parity of the y coordinate of R, and bit one is
+// set if the x coordinate of R is greater than or equal to n.
+func sign(priv, hash []byte, opts *Opts, nextNonce func() (*secp256k1.Scalar, error)) (r, s *secp256k1.Scalar, recid byte, err error) {
+	x, err := new(secp256k1.Scalar).SetBytes(priv)
+	if err != nil || x.IsZero() == 1 {
+		return nil, nil, 0, errors.New("ecdsa: invalid private key")
+	}
+	e := secp256k1.HashToScalar(hash)
+
+	for {
+		k, err := nextNonce()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if k.IsZero() == 1 {
+			continue
+		}
+
+		// R = [k]G, r = R.x mod n
+		R, err := secp256k1.NewPoint().ScalarBaseMult(k.Bytes())
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		encR := R.Bytes()
+		Rx := encR[1 : 1+secp256k1.ElementLength]
+		r = new(secp256k1.Scalar).SetBytesReduce(Rx)
+		if r.IsZero() == 1 {
+			continue
+		}
+		recid = encR[len(encR)-1] & 1
+		if !bytes.Equal(r.Bytes(), Rx) {
+			recid |= 2
+		}
+
+		// s = k⁻¹(e + r·x)
+		s = new(secp256k1.Scalar).Mul(r, x)
+		s.Add(s, e)
+		s.Mul(s, k.Invert(k))
+		if s.IsZero() == 1 {
+			continue
+		}
+
+		if opts != nil && opts.LowS && s.IsHigh() == 1 {
+			// (r, n - s) is the signature for the nonce -k, whose R has the
+			// opposite y coordinate.
+			s.Negate(s)
+			recid ^= 1
+		}
+		return r, s, recid, nil
+	}
+}
+
+// Verify reports whether (r, s) is a valid signature of hash by the public key
+// pub, which must be a SEC 1 encoded point. If the hash is longer than 256
+// bits, it is truncated to its leftmost 256 bits.
+//
+// Both high-S and low-S signatures are accepted. Use VerifyWithOpts to reject
+// high-S signatures.
+func Verify(pub, hash, r, s []byte) bool {
+	return VerifyWithOpts(pub, hash, r, s, nil)
+}
+
+// VerifyWithOpts is like Verify, but accepts verification options. A nil opts
+// is equivalent to the zero value.
+func VerifyWithOpts(pub, hash, r, s []byte, opts *Opts) bool {
+	Q, err := secp256k1.NewPoint().SetBytes(pub)
+	if err != nil || Q.IsInfinity() == 1 {
+		return false
+	}
+	rs, err := parseScalar(r)
+	if err != nil {
+		return false
+	}
+	ss, err := parseScalar(s)
+	if err != nil {
+		return false
+	}
+	if opts != nil && opts.LowS && ss.IsHigh() == 1 {
+		return false
+	}
+	e := secp256k1.HashToScalar(hash)
+
+	// R = [e·s⁻¹]G + [r·s⁻¹]Q
+	w := new(secp256k1.Scalar).Invert(ss)
+	u1 := new(secp256k1.Scalar).Mul(e, w)
+	u2 := new(secp256k1.Scalar).Mul(rs, w)
+	R, err := secp256k1.NewPoint().ScalarDoubleBaseMult(u1.Bytes(), Q, u2.Bytes())
+	if err != nil {
+		return false
+	}
+	Rx, err := R.BytesX()
+	if err != nil {
+		return false
+	}
+	v := new(secp256k1.Scalar).SetBytesReduce(Rx)
+	return v.Equal(rs) == 1
+}
+
+// parseScalar decodes a big-endian signature value, which must be in the
+// range [1, n). Values shorter than 32 bytes are zero-extended.
+func parseScalar(b []byte) (*secp256k1.Scalar, error) {
+	if len(b) > secp256k1.ElementLength {
+		return nil, errors.New("ecdsa: invalid signature value")
+	}
+	var buf [secp256k1.ElementLength]byte
+	copy(buf[secp256k1.ElementLength-len(b):], b)
+	v, err := new(secp256k1.Scalar).SetBytes(buf[:])
+	if err != nil || v.IsZero() == 1 {
+		return nil, errors.New("ecdsa: invalid signature value")
+	}
+	return v, nil
+}
+
+// randomScalar returns a uniformly random scalar read from rand, using
+// rejection sampling to avoid any bias.
+func randomScalar(rand io.Reader) (*secp256k1.Scalar, error) {
+	var buf [secp256k1.ElementLength]byte
+	for {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return nil, err
+		}
+		if s, err := new(secp256k1.Scalar).SetBytes(buf[:]); err == nil {
+			return s, nil
+		}
+	}
+}
+
+// RecoverPublicKey returns the public key that produced the signature (r, s)
+// of hash, given the recovery ID recid produced at signing time. Bit zero of
+// recid is the parity of the y coordinate of R, and bit one is set if the x
+// coordinate of R is r + n rather than r.
+//
+// RecoverPublicKey doesn't check whether s is low, and it is NOT constant
+// time, as signatures and public keys are public values.
+func RecoverPublicKey(hash, r, s []byte, recid int) (*secp256k1.Point, error) {
+	if recid < 0 || recid > 3 {
+		return nil, errors.New("ecdsa: invalid recovery ID")
+	}
+	rs, err := parseScalar(r)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := parseScalar(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconstruct R from its x coordinate, r or r + n, and the parity of y.
+	enc := make([]byte, 1+secp256k1.ElementLength)
+	enc[0] = 2 | byte(recid&1)
+	copy(enc[1:], rs.Bytes())
+	if recid&2 != 0 {
+		order := secp256k1.Order()
+		var carry uint16
+		for i := secp256k1.ElementLength - 1; i >= 0; i-- {
+			carry += uint16(enc[1+i]) + uint16(order[i])
+			enc[1+i] = byte(carry)
+			carry >>= 8
+		}
+		if carry != 0 {
+			return nil, errors.New("ecdsa: invalid signature for recovery")
+		}
+	}
+	R, err := secp256k1.NewPoint().SetBytes(enc)
+	if err != nil {
+		return nil, errors.New("ecdsa: invalid signature for recovery")
+	}
+
+	// Q = r⁻¹(s·R − e·G) = [−e·r⁻¹]G + [s·r⁻¹]R
+	e := secp256k1.HashToScalar(hash)
+	rInv := new(secp256k1.Scalar).Invert(rs)
+	u1 := new(secp256k1.Scalar).Mul(e, rInv)
+	u1.Negate(u1)
+	u2 := new(secp256k1.Scalar).Mul(ss, rInv)
+	Q, err := secp256k1.NewPoint().ScalarDoubleBaseMult(u1.Bytes(), R, u2.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if Q.IsInfinity() == 1 {
+		return nil, errors.New("ecdsa: invalid signature for recovery")
+	}
+	return Q, nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func generateKey(t *testing.T) (priv, pub []byte) {
+	t.Helper()
+	s, err := randomScalar(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := secp256k1.NewPoint().ScalarBaseMult(s.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.Bytes(), p.Bytes()
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, opts := range []*Opts{nil, {LowS: true}} {
+		priv, pub := generateKey(t)
+		hash := sha256.Sum256([]byte("testing"))
+		for i := 0; i < 16; i++ {
+			r, s, err := SignWithOpts(priv, hash[:], rand.Reader, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyWithOpts(pub, hash[:], r, s, opts) {
+				t.Fatalf("valid signature rejected")
+			}
+			if opts != nil && opts.LowS && bytes.Compare(s, secp256k1.HalfOrder()) > 0 {
+				t.Errorf("high-S signature produced with LowS")
+			}
+			hash[0] ^= 0xff
+			if Verify(pub, hash[:], r, s) {
+				t.Errorf("signature verified for a different hash")
+			}
+		}
+	}
+}
+
+func TestSignLongHash(t *testing.T) {
+	// Hashes longer than 256 bits are truncated to their leftmost 256 bits.
+	priv, pub := generateKey(t)
+	hash := sha512.Sum512([]byte("testing"))
+	r, s, err := Sign(priv, hash[:], rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(pub, hash[:], r, s) {
+		t.Fatal("valid signature rejected")
+	}
+	if !Verify(pub, hash[:32], r, s) {
+		t.Error("signature over a long hash does not match the truncated hash")
+	}
+}
+
+func TestSignInvalidKey(t *testing.T) {
+	n := decodeHex(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	for _, priv := range [][]byte{make([]byte, 32), n, {1}} {
+		if _, _, err := Sign(priv, []byte("hash"), rand.Reader); err == nil {
+			t.Errorf("Sign(%x) succeeded", priv)
+		}
+	}
+}
+
+func TestVerifyVectors(t *testing.T) {
+	// The RFC 6979 signature of SHA-256("Satoshi Nakamoto") with private key
+	// 1, whose public key is the generator, and edge cases derived from it in
+	// the spirit of the Wycheproof ECDSA test vectors.
+	pub := secp256k1.NewGenerator().Bytes()
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	r := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8"
+	s := "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	// n - s, the high-S equivalent of s.
+	sHigh := "dbbd3162d46e9f9bef7feb87c16dc13b4f6568a87f4e83f728e2443ba586675c"
+	// A signature of the same hash with the nonce 153, whose r has a leading
+	// zero byte, so that it also has a 31-byte encoding.
+	rZero := "00e3ae1974566ca06cc516d47e0fb165a674a3dabcfca15e722f0e3450f45889"
+	sZero := "5ec1ac78a2b610c3e59967f5462ef60b369ba485d41a44f0245e492023af6b57"
+	n := "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
+	zero := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	tests := []struct {
+		name string
+		pub  []byte
+		r, s string
+		lowS bool
+		want bool
+	}{
+		{"valid", pub, r, s, false, true},
+		{"valid low-S", pub, r, s, true, true},
+		{"high-S", pub, r, sHigh, false, true},
+		{"high-S rejected", pub, r, sHigh, true, false},
+		{"r is zero", pub, zero, s, false, false},
+		{"s is zero", pub, r, zero, false, false},
+		{"r is n", pub, n, s, false, false},
+		{"s is n", pub, r, n, false, false},
+		{"r plus n", pub, "01" + r, s, false, false},
+		{"r and s swapped", pub, s, r, false, false},
+		{"r with leading zero", pub, rZero, sZero, false, true},
+		{"short r", pub, rZero[2:], sZero, false, true},
+		{"r with top byte cleared", pub, "00" + r[2:], s, false, false},
+		{"compressed key", secp256k1.NewGenerator().BytesCompressed(), r, s, false, true},
+		{"wrong key", secp256k1.NewPoint().Double(secp256k1.NewGenerator()).Bytes(), r, s, false, false},
+		{"infinity", []byte{0}, r, s, false, false},
+		{"invalid key", []byte{4, 1, 2, 3}, r, s, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VerifyWithOpts(tt.pub, hash[:], decodeHex(t, tt.r), decodeHex(t, tt.s), &Opts{LowS: tt.lowS})
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	priv := bytes.Repeat([]byte{0x42}, 32)
+	hash := sha256.Sum256([]byte("testing"))
+	for i := 0; i < b.N; i++ {
+		Sign(priv, hash[:], rand.Reader)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	priv := bytes.Repeat([]byte{0x42}, 32)
+	pub, _ := secp256k1.NewPoint().ScalarBaseMult(priv)
+	hash := sha256.Sum256([]byte("testing"))
+	r, s, _ := Sign(priv, hash[:], rand.Reader)
+	for i := 0; i < b.N; i++ {
+		Verify(pub.Bytes(), hash[:], r, s)
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	priv, pub := generateKey(t)
+	hash := sha256.Sum256([]byte("testing"))
+	for _, opts := range []*Opts{nil, {LowS: true}} {
+		for i := 0; i < 16; i++ {
+			r, s, recid, err := sign(priv, hash[:], opts, func() (*secp256k1.Scalar, error) {
+				return randomScalar(rand.Reader)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			Q, err := RecoverPublicKey(hash[:], r.Bytes(), s.Bytes(), int(recid))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(Q.Bytes(), pub) {
+				t.Errorf("recovered %x, want %x", Q.Bytes(), pub)
+			}
+			Q, err = RecoverPublicKey(hash[:], r.Bytes(), s.Bytes(), int(recid^1))
+			if err == nil && bytes.Equal(Q.Bytes(), pub) {
+				t.Errorf("wrong recovery ID recovered the public key")
+			}
+		}
+	}
+}
+
+func TestRecoverPublicKeyInvalid(t *testing.T) {
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	r := decodeHex(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8")
+	s := decodeHex(t, "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5")
+	for _, recid := range []int{-1, 4} {
+		if _, err := RecoverPublicKey(hash[:], r, s, recid); err == nil {
+			t.Errorf("recid %d: expected error", recid)
+		}
+	}
+	// r + n is larger than p for this r.
+	if _, err := RecoverPublicKey(hash[:], r, s, 2); err == nil {
+		t.Error("expected error for r + n >= p")
+	}
+	if _, err := RecoverPublicKey(hash[:], make([]byte, 32), s, 0); err == nil {
+		t.Error("expected error for r = 0")
+	}
+	// x = 5 is not the x coordinate of any point, as 5³ + 7 is not a square.
+	five := make([]byte, 32)
+	five[31] = 5
+	if _, err := RecoverPublicKey(hash[:], five, s, 0); err == nil {
+		t.Error("expected error for r that doesn't decompress")
+	}
+
+	// The signature was made by private key 1, and exactly one of the two
+	// parities recovers the generator.
+	found := 0
+	for recid := 0; recid < 2; recid++ {
+		Q, err := RecoverPublicKey(hash[:], r, s, recid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found += Q.Equal(secp256k1.NewGenerator())
+	}
+	if found != 1 {
+		t.Errorf("generator recovered %d times, want once", found)
+	}
+}
+
+func TestCanonicalizeSignature(t *testing.T) {
+	priv, pub := generateKey(t)
+	hash := sha256.Sum256([]byte("testing"))
+	for i := 0; i < 16; i++ {
+		r, s, err := Sign(priv, hash[:], rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss, err := new(secp256k1.Scalar).SetBytes(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		high := ss.IsHigh() == 1
+		ss.Canonicalize()
+		low := ss.Bytes()
+		if ss.IsHigh() != 0 {
+			t.Fatalf("canonical s %x is high", low)
+		}
+		if !high && !bytes.Equal(low, s) {
+			t.Errorf("Canonicalize changed low s %x to %x", s, low)
+		}
+		if !Verify(pub, hash[:], r, low) || !VerifyWithOpts(pub, hash[:], r, low, &Opts{LowS: true}) {
+			t.Errorf("canonicalized signature rejected")
+		}
+		if high && VerifyWithOpts(pub, hash[:], r, s, &Opts{LowS: true}) {
+			t.Errorf("high-S signature accepted with LowS")
+		}
+	}
+}
+
+func TestSignWithNonce(t *testing.T) {
+	// The RFC 6979 nonce for this key and message, which must reproduce the
+	// published deterministic signature (in its high-S form).
+	priv := decodeHex(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	k, err := new(secp256k1.Scalar).SetBytes(decodeHex(t, "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kBytes := k.Bytes()
+	r, s, err := SignWithNonce(priv, hash[:], k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantR := decodeHex(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8")
+	wantS := decodeHex(t, "dbbd3162d46e9f9bef7feb87c16dc13b4f6568a87f4e83f728e2443ba586675c")
+	if !bytes.Equal(r, wantR) || !bytes.Equal(s, wantS) {
+		t.Errorf("got (%x, %x), want (%x, %x)", r, s, wantR, wantS)
+	}
+	if !bytes.Equal(k.Bytes(), kBytes) {
+		t.Error("SignWithNonce modified the nonce")
+	}
+
+	if _, _, err := SignWithNonce(priv, hash[:], new(secp256k1.Scalar)); err == nil {
+		t.Error("expected error for a zero nonce")
+	}
+	if _, _, err := SignWithNonce(make([]byte, 32), hash[:], k); err == nil {
+		t.Error("expected error for a zero private key")
+	}
+
+	// A private key x = -e/r makes s = k⁻¹(e + r·x) zero for this nonce.
+	rs, _ := new(secp256k1.Scalar).SetBytes(r)
+	x := new(secp256k1.Scalar).Invert(rs)
+	x.Mul(x, secp256k1.HashToScalar(hash[:]))
+	x.Negate(x)
+	if _, _, err := SignWithNonce(x.Bytes(), hash[:], k); err == nil {
+		t.Error("expected error for a nonce producing s = 0")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"crypto/hmac"
+	"hash"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// SignDeterministic signs hash using the private key priv, like Sign, but
+// derives the nonce deterministically from the private key and the hash as
+// specified in RFC 6979, Section 3.2, using HMAC with the hash function h.
+//
+// The same key and hash always produce the same signature, and the security
+// of the signature doesn't depend on a random number generator.
+func SignDeterministic(priv, hash []byte, h func() hash.Hash) (r, s []byte, err error) {
+	rs, ss, _, err := sign(priv, hash, nil, rfc6979Nonces(priv, hash, h))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rs.Bytes(), ss.Bytes(), nil
+}
+
+// rfc6979Nonces returns a generator of the sequence of nonces defined by
+// RFC 6979, Section 3.2. Candidates that are not in [1, n) are skipped inside
+// the HMAC-DRBG loop, and each call after the first one continues from step
+// h.3, as required when a nonce produces an invalid signature.
+//
+// priv must be a valid private key, which sign checks before drawing any nonce.
+func rfc6979Nonces(priv, hash []byte, h func() hash.Hash) func() (*secp256k1.Scalar, error) {
+	// Both bits2octets(h1) and int2octets(x) are 32 bytes long, as qlen is 256.
+	h1 := secp256k1.HashToScalar(hash).Bytes()
+
+	size := h().Size()
+	V := make([]byte, size)
+	K := make([]byte, size)
+	for i := range V {
+		V[i] = 0x01
+	}
+	mac := func(data ...[]byte) []byte {
+		m := hmac.New(h, K)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+
+	// Steps d. to g.
+	K = mac(V, []byte{0x00}, priv, h1)
+	V = mac(V)
+	K = mac(V, []byte{0x01}, priv, h1)
+	V = mac(V)
+
+	first := true
+	return func() (*secp256k1.Scalar, error) {
+		for {
+			if !first {
+				K = mac(V, []byte{0x00})
+				V = mac(V)
+			}
+			first = false
+
+			// Step h.2, where bits2int takes the leftmost 256 bits of T.
+			var T []byte
+			for len(T) < secp256k1.ElementLength {
+				V = mac(V)
+				T = append(T, V...)
+			}
+			k, err := new(secp256k1.Scalar).SetBytes(T[:secp256k1.ElementLength])
+			if err == nil && k.IsZero() == 0 {
+				return k, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func TestRFC6979Nonces(t *testing.T) {
+	// Nonces for SHA-256 from the Trezor and CoreBitcoin test suites.
+	tests := []struct {
+		key, msg, nonce string
+	}{
+		{
+			"cca9fbcc1b41e5a95d369eaa6ddcff73b61a4efaa279cfc6567e8daa39cbaf50",
+			"sample",
+			"2df40ca70e639d89528a6b670d9d48d9165fdc0febc0974056bdce192b8e16a3",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"Satoshi Nakamoto",
+			"8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+		},
+		{
+			"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			"Satoshi Nakamoto",
+			"33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90",
+		},
+		{
+			"f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+			"Alan Turing",
+			"525a82b70e67874398067543fd84c83d30c175fdc45fdeee082fe13b1d7cfdf1",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000001",
+			"All those moments will be lost in time, like tears in rain. Time to die...",
+			"38aa22d72376b4dbc472e06c3ba403ee0a394da63fc58d88686c611aba98d6b3",
+		},
+	}
+	for _, tt := range tests {
+		hash := sha256.Sum256([]byte(tt.msg))
+		k, err := rfc6979Nonces(decodeHex(t, tt.key), hash[:], sha256.New)()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := k.Bytes(); !bytes.Equal(got, decodeHex(t, tt.nonce)) {
+			t.Errorf("%q: got nonce %x, want %s", tt.msg, got, tt.nonce)
+		}
+	}
+}
+
+func TestSignDeterministic(t *testing.T) {
+	priv := decodeHex(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	r, s, err := SignDeterministic(priv, hash[:], sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The published signature is normalized to low-S, while SignDeterministic
+	// returns the high-S value n - s.
+	wantR := decodeHex(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8")
+	wantS := decodeHex(t, "dbbd3162d46e9f9bef7feb87c16dc13b4f6568a87f4e83f728e2443ba586675c")
+	if !bytes.Equal(r, wantR) || !bytes.Equal(s, wantS) {
+		t.Errorf("got (%x, %x), want (%x, %x)", r, s, wantR, wantS)
+	}
+
+	r2, s2, err := SignDeterministic(priv, hash[:], sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, r2) || !bytes.Equal(s, s2) {
+		t.Error("signatures of the same hash differ")
+	}
+
+	// Any hash function can drive the DRBG.
+	r, s, err = SignDeterministic(priv, hash[:], sha512.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verify(secp256k1.NewGenerator().Bytes(), hash[:], r, s) {
+		t.Error("HMAC-SHA-512 signature rejected")
+	}
+}
+
+func TestRFC6979NoncesContinue(t *testing.T) {
+	// Successive nonces, used when a nonce yields r = 0 or s = 0, must differ.
+	priv := decodeHex(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	hash := sha256.Sum256([]byte("sample"))
+	next := rfc6979Nonces(priv, hash[:], sha256.New)
+	k1, _ := next()
+	k2, _ := next()
+	if k1.Equal(k2) == 1 {
+		t.Error("nonce generator repeated a nonce")
+	}
+}
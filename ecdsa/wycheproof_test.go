@@ -0,0 +1,68 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// wycheproofVerify is the subset of the Wycheproof ecdsa_verify_schema.json
+// format used by TestWycheproof.
+type wycheproofVerify struct {
+	NumberOfTests int `json:"numberOfTests"`
+	TestGroups    []struct {
+		PublicKey struct {
+			Uncompressed string `json:"uncompressed"`
+		} `json:"publicKey"`
+		SHA   string `json:"sha"`
+		Tests []struct {
+			TcID    int      `json:"tcId"`
+			Comment string   `json:"comment"`
+			Flags   []string `json:"flags"`
+			Msg     string   `json:"msg"`
+			Sig     string   `json:"sig"`
+			Result  string   `json:"result"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+// TestWycheproof runs the DER-encoded signatures of
+// testdata/ecdsa_secp256k1_sha256_test.json through ParseDERSignature and
+// Verify. A signature that fails to parse counts as rejected.
+func TestWycheproof(t *testing.T) {
+	data, err := os.ReadFile("testdata/ecdsa_secp256k1_sha256_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors wycheproofVerify
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	for _, g := range vectors.TestGroups {
+		if g.SHA != "SHA-256" {
+			t.Fatalf("unexpected hash %q", g.SHA)
+		}
+		pub := decodeHex(t, g.PublicKey.Uncompressed)
+		for _, tt := range g.Tests {
+			n++
+			hash := sha256.Sum256(decodeHex(t, tt.Msg))
+			var got bool
+			if r, s, err := ParseDERSignature(decodeHex(t, tt.Sig)); err == nil {
+				got = Verify(pub, hash[:], r, s)
+			}
+			if want := tt.Result == "valid"; got != want {
+				t.Errorf("tcId %d (%s, %v): got %v, want %s", tt.TcID, tt.Comment, tt.Flags, got, tt.Result)
+			}
+		}
+	}
+	if n != vectors.NumberOfTests {
+		t.Errorf("ran %d tests, want %d", n, vectors.NumberOfTests)
+	}
+}
@@ -5,10 +5,34 @@
 package secp256k1
 
 import (
+	"bytes"
 	"crypto/subtle"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
 )
 
+// fieldPrime is p = 2^256 - 2^32 - 977 as little-endian 64-bit limbs.
+var fieldPrime = [4]uint64{0xfffffffefffffc2f, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}
+
+// P returns the 32-byte big-endian encoding of the field modulus,
+// p = 2^256 - 2^32 - 977. The returned slice is a fresh copy.
+func P() []byte {
+	return limbsBytes(&fieldPrime)
+}
+
+// limbsBytes returns the 32-byte big-endian encoding of little-endian limbs.
+func limbsBytes(l *[4]uint64) []byte {
+	out := make([]byte, ElementLength)
+	for i := range l {
+		binary.BigEndian.PutUint64(out[ElementLength-8*(i+1):], l[i])
+	}
+	return out
+}
+
 // Element is an integer modulo 2^256 - 2^32 - 977.
 //
 // The zero value is a valid zero element.
@@ -23,18 +47,46 @@ func (e *Element) One() *Element {
 	return e
 }
 
-// Equal returns 1 if e == t, and zero otherwise.
+// Equal returns 1 if e == t, and zero otherwise. It runs in constant time.
 func (e *Element) Equal(t *Element) int {
-	eBytes := e.Bytes()
-	tBytes := t.Bytes()
-	return subtle.ConstantTimeCompare(eBytes, tBytes)
+	// The comparison happens in the Montgomery domain, which is sound because
+	// the Montgomery representation of each value lower than p is unique.
+	// Limbs set directly to a value in [p, 2^256) are reduced first, as Bytes
+	// would do.
+	a, b := *e, *t
+	a.reduce()
+	b.reduce()
+	return a.equal(&b)
 }
 
-// IsZero returns 1 if e == 0, and zero otherwise.
+// IsZero returns 1 if e == 0, and zero otherwise. It runs in constant time.
 func (e *Element) IsZero() int {
-	zero := make([]byte, ElementLength)
-	eBytes := e.Bytes()
-	return subtle.ConstantTimeCompare(eBytes, zero)
+	a := *e
+	a.reduce()
+	acc := a[0] | a[1] | a[2] | a[3]
+	return int((acc|-acc)>>63) ^ 1
+}
+
+// isReduced reports whether the limbs of e are lower than p, which is the
+// invariant kept by all arithmetic operations. It is not constant time, and
+// is meant for tests.
+func (e *Element) isReduced() bool {
+	return cmpLimbs((*[4]uint64)(e), &fieldPrime) < 0
+}
+
+// reduce subtracts p from e if e is not lower than p, and returns e. It runs
+// in constant time. Any value lower than 2p, including any 256-bit value, is
+// fully reduced after a single call.
+func (e *Element) reduce() *Element {
+	s0, borrow := bits.Sub64(e[0], fieldPrime[0], 0)
+	s1, borrow := bits.Sub64(e[1], fieldPrime[1], borrow)
+	s2, borrow := bits.Sub64(e[2], fieldPrime[2], borrow)
+	s3, borrow := bits.Sub64(e[3], fieldPrime[3], borrow)
+	e[0] = cmovznz(borrow, s0, e[0])
+	e[1] = cmovznz(borrow, s1, e[1])
+	e[2] = cmovznz(borrow, s2, e[2])
+	e[3] = cmovznz(borrow, s3, e[3])
+	return e
 }
 
 // Set sets e = t, and returns e.
@@ -59,34 +111,179 @@ func (e *Element) bytes(out *[ElementLength]byte) []byte {
 	return out[:]
 }
 
+// IsOdd returns 1 if e is odd, and zero otherwise. It runs in constant time,
+// and is cheaper than reading the last byte of Bytes.
+func (e *Element) IsOdd() int {
+	var tmp Element
+	fromMontgomery(&tmp, e)
+	return int(tmp[0] & 1)
+}
+
 // SetBytes sets e = v, where v is a big-endian 32-byte encoding, and returns e.
 // If v is not 32 bytes or it encodes a value higher than 2^256 - 2^32 - 977,
 // SetBytes returns nil and an error, and e is unchanged.
 func (e *Element) SetBytes(v []byte) (*Element, error) {
 	if len(v) != ElementLength {
-		return nil, errors.New("invalid Element encoding")
+		return nil, errElementLength
+	}
+	if e.setCanonicalBytes(v) != 1 {
+		return nil, errElementNonCanonical
 	}
+	return e, nil
+}
 
-	// Check for non-canonical encodings (p + k, 2p + k, etc.) by comparing to
-	// the encoding of -1 mod p, so p - 1, the highest canonical encoding.
-	minusOneEncoding := new(Element).Sub(
-		new(Element), new(Element).One()).Bytes()
-	for i := range v {
-		if v[i] < minusOneEncoding[i] {
-			break
-		}
-		if v[i] > minusOneEncoding[i] {
-			return nil, errors.New("invalid Element encoding")
-		}
+// SetBytesCanonical sets e = v mod p, where v is a big-endian 32-byte encoding,
+// and reports whether v was reduced, that is whether it was a non-canonical
+// encoding of a value not lower than p. If v is not 32 bytes,
+// SetBytesCanonical returns an error and e is unchanged.
+//
+// Unlike SetBytes, it accepts non-canonical encodings, so callers can tell
+// them apart from malformed input. It runs in constant time.
+func (e *Element) SetBytesCanonical(v []byte) (reduced bool, err error) {
+	if len(v) != ElementLength {
+		return false, errElementLength
 	}
+	limbs := limbsFromBytes(v)
+	// v < 2^256 < 2p, so if v >= p, v - p is already reduced.
+	tmp := limbs
+	tmp.reduce()
+	toMontgomery(e, &tmp)
+	return tmp.equal(&limbs) == 0, nil
+}
 
+// BytesLE returns the 32-byte little-endian encoding of e.
+func (e *Element) BytesLE() []byte {
+	var out [ElementLength]byte
+	b := e.bytes(&out)
+	invertEndianness(b)
+	return b
+}
+
+// SetBytesLE sets e = v, where v is a little-endian 32-byte encoding, and
+// returns e. Like SetBytes, if v is not 32 bytes or it encodes a value higher
+// than 2^256 - 2^32 - 977, SetBytesLE returns nil and an error, and e is
+// unchanged.
+func (e *Element) SetBytesLE(v []byte) (*Element, error) {
+	if len(v) != ElementLength {
+		return nil, errElementLength
+	}
 	var in [ElementLength]byte
 	copy(in[:], v)
 	invertEndianness(in[:])
-	var tmp Element
-	fromBytes(&tmp, &in)
-	toMontgomery(e, &tmp)
-	return e, nil
+	return e.SetBytes(in[:])
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the 32-byte
+// big-endian encoding of e.
+func (e *Element) MarshalBinary() ([]byte, error) {
+	return e.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It accepts the same
+// encodings as SetBytes, and rejects non-canonical ones.
+func (e *Element) UnmarshalBinary(data []byte) error {
+	_, err := e.SetBytes(data)
+	return err
+}
+
+// String returns the canonical value of e as a 0x-prefixed, 64-digit hex
+// string. It is meant for debugging, and is not constant time.
+func (e *Element) String() string {
+	var buf [ElementLength]byte
+	var out [2 + 2*ElementLength]byte
+	return string(appendHex(append(out[:0], "0x"...), e.bytes(&buf)))
+}
+
+// Format implements fmt.Formatter. The %x and %X verbs print the canonical
+// value of e as 64 hex digits, with a 0x or 0X prefix if the # flag is set,
+// and the %v and %s verbs print the same as String.
+func (e *Element) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'x', 'X':
+		var buf [ElementLength]byte
+		var out [2 + 2*ElementLength]byte
+		digits := out[:0]
+		if f.Flag('#') {
+			digits = append(digits, "0x"...)
+		}
+		digits = appendHex(digits, e.bytes(&buf))
+		if verb == 'X' {
+			digits = bytes.ToUpper(digits)
+		}
+		f.Write(digits)
+	case 'v', 's':
+		io.WriteString(f, e.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(*secp256k1.Element=%s)", verb, e.String())
+	}
+}
+
+// Big returns the canonical value of e as a new big.Int.
+func (e *Element) Big() *big.Int {
+	return new(big.Int).SetBytes(e.Bytes())
+}
+
+// SetBig sets e = v, and returns e. If v is negative or not lower than
+// 2^256 - 2^32 - 977, SetBig returns nil and an error, and e is unchanged.
+func (e *Element) SetBig(v *big.Int) (*Element, error) {
+	if v.Sign() < 0 || v.BitLen() > 8*ElementLength {
+		return nil, errors.New("invalid Element value")
+	}
+	var buf [ElementLength]byte
+	return e.SetBytes(v.FillBytes(buf[:]))
+}
+
+// setCanonicalBytes sets e = v, where v is a big-endian 32-byte encoding, and
+// returns 1 if v encodes a value lower than p. Otherwise, it returns 0 and e is
+// unchanged. It runs in constant time, and is the range check behind SetBytes.
+func (e *Element) setCanonicalBytes(v []byte) int {
+	limbs := limbsFromBytes(v)
+	// cmpLimbs returns -1, whose sign bit is set, only if v < p.
+	ok := int(uint(cmpLimbs((*[4]uint64)(&limbs), &fieldPrime)) >> (bits.UintSize - 1))
+	var out Element
+	toMontgomery(&out, &limbs)
+	e.Select(&out, e, ok)
+	return ok
+}
+
+// limbsFromBytes returns the big-endian 32-byte encoding v as little-endian
+// limbs, outside the Montgomery domain and not reduced modulo p.
+func limbsFromBytes(v []byte) Element {
+	var limbs Element
+	for i := range limbs {
+		limbs[i] = binary.BigEndian.Uint64(v[ElementLength-8*(i+1):])
+	}
+	return limbs
+}
+
+// equal returns 1 if e == t, and zero otherwise. Elements are always fully
+// reduced, so their Montgomery representation is unique and can be compared
+// limb by limb without leaving the Montgomery domain.
+func (e *Element) equal(t *Element) int {
+	acc := (e[0] ^ t[0]) | (e[1] ^ t[1]) | (e[2] ^ t[2]) | (e[3] ^ t[3])
+	return int((acc|-acc)>>63) ^ 1
+}
+
+// Cmp returns -1 if e < t, 0 if e == t, and 1 if e > t, comparing the
+// canonical integer values of e and t. It runs in constant time.
+func (e *Element) Cmp(t *Element) int {
+	var a, b Element
+	fromMontgomery(&a, e)
+	fromMontgomery(&b, t)
+	return cmpLimbs((*[4]uint64)(&a), (*[4]uint64)(&b))
+}
+
+// cmpLimbs returns -1 if a < b, 0 if a == b, and 1 if a > b, where a and b are
+// little-endian 256-bit integers. It runs in constant time.
+func cmpLimbs(a, b *[4]uint64) int {
+	var lt, gt uint64
+	for i := 0; i < 4; i++ {
+		_, lt = bits.Sub64(a[i], b[i], lt)
+	}
+	for i := 0; i < 4; i++ {
+		_, gt = bits.Sub64(b[i], a[i], gt)
+	}
+	return int(gt) - int(lt)
 }
 
 // Select sets v to a if cond == 1, and to b if cond == 0.
@@ -99,107 +296,308 @@ func (e *Element) Select(a, b *Element, cond int) *Element {
 	return e
 }
 
+// Double sets e = t + t, and returns e.
+func (e *Element) Double(t *Element) *Element {
+	// A left shift by one bit, followed by a conditional subtraction of p.
+	d0 := t[0] << 1
+	d1 := t[1]<<1 | t[0]>>63
+	d2 := t[2]<<1 | t[1]>>63
+	d3 := t[3]<<1 | t[2]>>63
+	carry := t[3] >> 63
+	s0, borrow := bits.Sub64(d0, 0xfffffffefffffc2f, 0)
+	s1, borrow := bits.Sub64(d1, 0xffffffffffffffff, borrow)
+	s2, borrow := bits.Sub64(d2, 0xffffffffffffffff, borrow)
+	s3, borrow := bits.Sub64(d3, 0xffffffffffffffff, borrow)
+	_, borrow = bits.Sub64(carry, 0, borrow)
+	e[0] = cmovznz(borrow, s0, d0)
+	e[1] = cmovznz(borrow, s1, d1)
+	e[2] = cmovznz(borrow, s2, d2)
+	e[3] = cmovznz(borrow, s3, d3)
+	return e
+}
+
+// Halve sets e = t / 2, and returns e. It runs in constant time, without an
+// inversion.
+func (e *Element) Halve(t *Element) *Element {
+	// Halving commutes with the Montgomery representation, so the limbs are
+	// halved directly: an odd value first gets p added, making it even, and
+	// the 257-bit sum is then shifted right by one bit.
+	mask := -(t[0] & 1)
+	h0, carry := bits.Add64(t[0], 0xfffffffefffffc2f&mask, 0)
+	h1, carry := bits.Add64(t[1], 0xffffffffffffffff&mask, carry)
+	h2, carry := bits.Add64(t[2], 0xffffffffffffffff&mask, carry)
+	h3, carry := bits.Add64(t[3], 0xffffffffffffffff&mask, carry)
+	e[0] = h0>>1 | h1<<63
+	e[1] = h1>>1 | h2<<63
+	e[2] = h2>>1 | h3<<63
+	e[3] = h3>>1 | carry<<63
+	return e
+}
+
+// Mul sets e = t1 * t2, and returns e.
+func (e *Element) Mul(t1, t2 *Element) *Element {
+	mul(e, t1, t2)
+	return e
+}
+
+// Square sets e = t * t, and returns e.
+func (e *Element) Square(t *Element) *Element {
+	square(e, t)
+	return e
+}
+
+// MulAdd sets e = a * b + c, and returns e. The arguments may overlap with e
+// and with each other.
+//
+// It saves the point formulas a temporary for the common pattern of adding a
+// product to an accumulator. The result is identical to a Mul followed by an
+// Add, as the Montgomery multiplication always returns a fully reduced value
+// which the addition then needs to reduce again.
+func (e *Element) MulAdd(a, b, c *Element) *Element {
+	var t Element
+	t.Mul(a, b)
+	return e.Add(&t, c)
+}
+
+// MulInt sets e = t * k, and returns e. It runs in constant time with respect
+// to the values of t and k.
+//
+// It is meant for multiplications by small constants such as the 3b of the
+// curve formulas, and is considerably cheaper than Mul: it needs a single row
+// of limb products, and the top limb is folded back using
+// 2^256 ≡ 2^32 + 977 mod p. Since the Montgomery representation is linear, k
+// does not need to be converted.
+func (e *Element) MulInt(t *Element, k uint64) *Element {
+	h0, l0 := bits.Mul64(t[0], k)
+	h1, l1 := bits.Mul64(t[1], k)
+	h2, l2 := bits.Mul64(t[2], k)
+	h3, l3 := bits.Mul64(t[3], k)
+	r0 := l0
+	r1, carry := bits.Add64(l1, h0, 0)
+	r2, carry := bits.Add64(l2, h1, carry)
+	r3, carry := bits.Add64(l3, h2, carry)
+	r4 := h3 + carry
+
+	// Fold r4 * 2^256 as r4 * (2^32 + 977).
+	hi, lo := bits.Mul64(r4, 0x1000003d1)
+	r0, carry = bits.Add64(r0, lo, 0)
+	r1, carry = bits.Add64(r1, hi, carry)
+	r2, carry = bits.Add64(r2, 0, carry)
+	r3, carry = bits.Add64(r3, 0, carry)
+
+	// If that overflowed, the low limbs are now small and folding the carry
+	// once more can't overflow again.
+	r0, carry = bits.Add64(r0, carry*0x1000003d1, 0)
+	r1, carry = bits.Add64(r1, 0, carry)
+	r2, carry = bits.Add64(r2, 0, carry)
+	r3, _ = bits.Add64(r3, 0, carry)
+
+	// The result is now lower than 2^256 < 2p, so subtract p at most once.
+	e[0], e[1], e[2], e[3] = r0, r1, r2, r3
+	return e.reduce()
+}
+
+// CondNegate sets e to -t if cond == 1, and to t if cond == 0, and returns e.
+// e and t can overlap.
+//
+// CondNegate runs in constant time: both -t and t are always computed, and
+// the result is chosen with Select, without branching on cond.
+func (e *Element) CondNegate(t *Element, cond int) *Element {
+	var negated Element
+	negated.Sub(&negated, t)
+	return e.Select(&negated, t, cond)
+}
+
 func invertEndianness(v []byte) {
 	for i := 0; i < len(v)/2; i++ {
 		v[i], v[len(v)-1-i] = v[len(v)-1-i], v[i]
 	}
 }
 
+// Sqrt sets e to the square root of x with an even canonical encoding, and
+// returns 1. If x is not a square, Sqrt returns 0 and e is unchanged. e and x
+// can overlap.
+//
+// Sqrt runs in constant time with respect to the value of x.
+func (e *Element) Sqrt(x *Element) (isSquare int) {
+	var candidate, square Element
+	sqrtCandidate(&candidate, x)
+	square.Square(&candidate)
+	isSquare = square.equal(x)
+
+	// Of the two roots r and p - r, exactly one is even (or both are zero).
+	odd := candidate.IsOdd()
+	candidate.CondNegate(&candidate, odd)
+
+	e.Select(&candidate, e, isSquare)
+	return isSquare
+}
+
+// Exp sets e = base^exp, where exp is a big-endian encoded integer, and
+// returns e. e and base can overlap.
+//
+// Exp uses a fixed four-bit window and scans the whole table for every
+// window, so it runs in constant time for exponents of a given length.
+func (e *Element) Exp(base *Element, exp []byte) *Element {
+	var table [16]Element
+	table[0].One()
+	table[1].Set(base)
+	for i := 2; i < 16; i++ {
+		table[i].Mul(&table[i-1], base)
+	}
+
+	z := new(Element).One()
+	t := new(Element)
+	for _, b := range exp {
+		for _, window := range [2]byte{b >> 4, b & 0b1111} {
+			z.Square(z)
+			z.Square(z)
+			z.Square(z)
+			z.Square(z)
+			for i := range table {
+				t.Select(&table[i], t, subtle.ConstantTimeByteEq(uint8(i), window))
+			}
+			z.Mul(z, t)
+		}
+	}
+	return e.Set(z)
+}
+
 // Invert sets e = 1/X, and returns e.
 //
 // If X == 0, Invert returns e = 0.
 func (e *Element) Invert(x *Element) *Element {
 	// Inversion is implemented as exponentiation with exponent p − 2.
-	// The sequence of 15 multiplications and 255 squarings is derived from the
-	// following addition chain generated with github.com/mmcloughlin/addchain v0.4.0.
-	//
-	//	_10     = 2*1
-	//	_100    = 2*_10
-	//	_101    = 1 + _100
-	//	_111    = _10 + _101
-	//	_1110   = 2*_111
-	//	_111000 = _1110 << 2
-	//	_111111 = _111 + _111000
-	//	i13     = _111111 << 4 + _1110
-	//	x12     = i13 << 2 + _111
-	//	x22     = x12 << 10 + i13 + 1
-	//	i29     = 2*x22
-	//	i31     = i29 << 2
-	//	i54     = i31 << 22 + i31
-	//	i122    = (i54 << 20 + i29) << 46 + i54
-	//	x223    = i122 << 110 + i122 + _111
-	//	i269    = ((x223 << 23 + x22) << 7 + _101) << 3
-	//	return    _101 + i269
-	//
-
-	var z = new(Element).Set(e)
-	var t0 = new(Element)
-	var t1 = new(Element)
-	var t2 = new(Element)
-	var t3 = new(Element)
-	var t4 = new(Element)
-
-	t0.Square(x)
-	z.Square(t0)
-	z.Mul(x, z)
-	t1.Mul(t0, z)
-	t0.Square(t1)
-	t2.Square(t0)
-	for s := 1; s < 2; s++ {
-		t2.Square(t2)
-	}
-	t2.Mul(t1, t2)
-	for s := 0; s < 4; s++ {
-		t2.Square(t2)
-	}
-	t0.Mul(t0, t2)
-	t2.Square(t0)
-	for s := 1; s < 2; s++ {
-		t2.Square(t2)
-	}
-	t2.Mul(t1, t2)
-	for s := 0; s < 10; s++ {
-		t2.Square(t2)
-	}
-	t0.Mul(t0, t2)
-	t0.Mul(x, t0)
-	t3.Square(t0)
-	t2.Square(t3)
-	for s := 1; s < 2; s++ {
-		t2.Square(t2)
-	}
-	t4.Square(t2)
-	for s := 1; s < 22; s++ {
-		t4.Square(t4)
-	}
-	t2.Mul(t2, t4)
-	t4.Square(t2)
-	for s := 1; s < 20; s++ {
-		t4.Square(t4)
-	}
-	t3.Mul(t3, t4)
-	for s := 0; s < 46; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	t3.Square(t2)
-	for s := 1; s < 110; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	t1.Mul(t1, t2)
-	for s := 0; s < 23; s++ {
-		t1.Square(t1)
-	}
-	t0.Mul(t0, t1)
-	for s := 0; s < 7; s++ {
-		t0.Square(t0)
-	}
-	t0.Mul(z, t0)
-	for s := 0; s < 3; s++ {
-		t0.Square(t0)
-	}
-	z.Mul(z, t0)
+	e.expChain(x, invertChain)
+	return e
+}
 
-	return e.Set(z)
+// invertChain computes x^(p − 2) with 15 multiplications and 255 squarings. It
+// is derived from the following addition chain generated with
+// github.com/mmcloughlin/addchain v0.4.0.
+//
+//	_10     = 2*1
+//	_100    = 2*_10
+//	_101    = 1 + _100
+//	_111    = _10 + _101
+//	_1110   = 2*_111
+//	_111000 = _1110 << 2
+//	_111111 = _111 + _111000
+//	i13     = _111111 << 4 + _1110
+//	x12     = i13 << 2 + _111
+//	x22     = x12 << 10 + i13 + 1
+//	i29     = 2*x22
+//	i31     = i29 << 2
+//	i54     = i31 << 22 + i31
+//	i122    = (i54 << 20 + i29) << 46 + i54
+//	x223    = i122 << 110 + i122 + _111
+//	i269    = ((x223 << 23 + x22) << 7 + _101) << 3
+//	return    _101 + i269
+var invertChain = []expStep{
+	{1, 0, 1, noMul},   // 1: _10
+	{2, 1, 1, noMul},   // 2: _100
+	{3, 2, 0, 0},       // 3: _101
+	{4, 3, 0, 1},       // 4: _111
+	{5, 4, 1, noMul},   // 5: _1110
+	{6, 5, 2, noMul},   // 6: _111000
+	{7, 6, 0, 4},       // 7: _111111
+	{8, 7, 4, 5},       // 8: i13
+	{9, 8, 2, 4},       // 9: x12
+	{10, 9, 10, 8},     // 10: x12 << 10 + i13
+	{11, 10, 0, 0},     // 11: x22
+	{12, 11, 1, noMul}, // 12: i29
+	{13, 12, 2, noMul}, // 13: i31
+	{14, 13, 22, 13},   // 14: i54
+	{15, 14, 20, 12},   // 15: i54 << 20 + i29
+	{16, 15, 46, 14},   // 16: i122
+	{17, 16, 110, 16},  // 17: i122 << 110 + i122
+	{18, 17, 0, 4},     // 18: x223
+	{19, 18, 23, 11},   // 19: x223 << 23 + x22
+	{20, 19, 7, 3},     // 20: ... << 7 + _101
+	{21, 20, 3, noMul}, // 21: i269
+	{22, 21, 0, 3},     // 22: _101 + i269
+}
+
+// noMul marks an expStep that only squares.
+const noMul = 0xff
+
+// expStep is a step of a fixed addition chain, evaluated by expChain. It sets
+// register dst to register src squared sq times and, unless mul is noMul,
+// multiplied by register mul. Register 0 holds the base.
+type expStep struct {
+	dst, src uint8
+	sq       uint8
+	mul      uint8
+}
+
+// maxExpRegisters is the number of registers available to an expStep chain.
+const maxExpRegisters = 32
+
+// expChain sets e to x raised to the exponent described by chain, which
+// returns the value of the register written by its last step. e and x can
+// overlap.
+//
+// The sequence of operations depends only on chain, so expChain runs in
+// constant time with respect to the value of x.
+func (e *Element) expChain(x *Element, chain []expStep) {
+	var r [maxExpRegisters]Element
+	r[0].Set(x)
+	var last uint8
+	for _, s := range chain {
+		t := &r[s.dst]
+		t.Set(&r[s.src])
+		for i := uint8(0); i < s.sq; i++ {
+			t.Square(t)
+		}
+		if s.mul != noMul {
+			t.Mul(t, &r[s.mul])
+		}
+		last = s.dst
+	}
+	e.Set(&r[last])
+}
+
+// InvertBatch sets out[i] = 1/in[i] for every i, using Montgomery's trick to
+// replace n inversions with a single one and 3(n - 1) multiplications.
+//
+// As with Invert, a zero input produces a zero output. Zero inputs are
+// replaced with one in constant time before they enter the running product,
+// so they don't affect the other outputs, and the running time doesn't depend
+// on which inputs are zero.
+//
+// out and in must have the same length, or InvertBatch panics. out[i] may
+// overlap with in[i], but not with any other input.
+func InvertBatch(out, in []*Element) {
+	if len(out) != len(in) {
+		panic("secp256k1: InvertBatch called with mismatched lengths")
+	}
+	if len(in) == 0 {
+		return
+	}
+
+	one := new(Element).One()
+	x := new(Element)
+
+	// prefix[i] is the product of all (non-zero) inputs up to and including i.
+	prefix := make([]Element, len(in))
+	prefix[0].Select(one, in[0], in[0].IsZero())
+	for i := 1; i < len(in); i++ {
+		x.Select(one, in[i], in[i].IsZero())
+		prefix[i].Mul(&prefix[i-1], x)
+	}
+
+	inv := new(Element).Invert(&prefix[len(in)-1])
+	r := new(Element)
+	for i := len(in) - 1; i >= 0; i-- {
+		isZero := in[i].IsZero()
+		x.Select(one, in[i], isZero)
+		// inv is the inverse of prefix[i], so 1/in[i] = inv * prefix[i-1].
+		if i > 0 {
+			r.Mul(inv, &prefix[i-1])
+		} else {
+			r.Set(inv)
+		}
+		inv.Mul(inv, x)
+		out[i].Select(new(Element), r, isZero)
+	}
 }
@@ -0,0 +1,791 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// pMinusOne is p - 1, the largest canonical field element.
+var pMinusOne = decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e")
+
+func randomElement(t testing.TB) *Element {
+	buf := make([]byte, ElementLength)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			t.Fatal(err)
+		}
+		if e, err := new(Element).SetBytes(buf); err == nil {
+			return e
+		}
+	}
+}
+
+// testElements returns a set of edge case and random elements.
+func testElements(t testing.TB) []*Element {
+	minusOne, err := new(Element).SetBytes(pMinusOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements := []*Element{new(Element), new(Element).One(), minusOne}
+	for i := 0; i < 16; i++ {
+		elements = append(elements, randomElement(t))
+	}
+	return elements
+}
+
+func elementFromUint64(t testing.TB, v uint64) *Element {
+	buf := make([]byte, ElementLength)
+	binary.BigEndian.PutUint64(buf[ElementLength-8:], v)
+	e, err := new(Element).SetBytes(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestMulBySmall(t *testing.T) {
+	for _, c := range []uint64{0, 1, 2, 7, b3, 0x1000003d1, 1<<63 + 12345, 1<<64 - 1} {
+		ce := elementFromUint64(t, c)
+		for _, x := range testElements(t) {
+			want := new(Element).Mul(x, ce)
+			got := new(Element).MulInt(x, c)
+			if *got != *want {
+				t.Errorf("%x * %d: got %x, want %x", x.Bytes(), c, got.Bytes(), want.Bytes())
+			}
+			// The receiver may overlap with the operand.
+			if y := new(Element).Set(x); *y.MulInt(y, c) != *want {
+				t.Errorf("%x * %d: aliased result differs", x.Bytes(), c)
+			}
+		}
+	}
+}
+
+func BenchmarkMulBySmall(b *testing.B) {
+	x := randomElement(b)
+	b.Run("Mul", func(b *testing.B) {
+		c := elementFromUint64(b, b3)
+		for i := 0; i < b.N; i++ {
+			x.Mul(x, c)
+		}
+	})
+	b.Run("MulInt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			x.MulInt(x, b3)
+		}
+	})
+}
+
+func TestElementDouble(t *testing.T) {
+	for _, x := range testElements(t) {
+		want := new(Element).Add(x, x)
+		if got := new(Element).Double(x); *got != *want {
+			t.Errorf("2 * %x: got %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+		// The receiver may overlap with the operand.
+		if y := new(Element).Set(x); *y.Double(y) != *want {
+			t.Errorf("2 * %x: aliased result differs", x.Bytes())
+		}
+	}
+}
+
+func TestElementHalve(t *testing.T) {
+	two := elementFromUint64(t, 2)
+	for _, x := range testElements(t) {
+		if got := new(Element).Halve(new(Element).Double(x)); *got != *x {
+			t.Errorf("(2 * %x) / 2: got %x", x.Bytes(), got.Bytes())
+		}
+		h := new(Element).Halve(x)
+		if got := new(Element).Mul(h, two); *got != *x {
+			t.Errorf("2 * (%x / 2): got %x", x.Bytes(), got.Bytes())
+		}
+		if cmpLimbs((*[4]uint64)(h), &fieldPrime) >= 0 {
+			t.Errorf("%x / 2: result is not reduced", x.Bytes())
+		}
+		// The receiver may overlap with the operand.
+		if y := new(Element).Set(x); *y.Halve(y) != *h {
+			t.Errorf("%x / 2: aliased result differs", x.Bytes())
+		}
+	}
+}
+
+func TestElementSqrt(t *testing.T) {
+	minusOne := new(Element).Sub(new(Element), new(Element).One())
+	minusThree := new(Element).Sub(new(Element), elementFromUint64(t, 3))
+	tests := []struct {
+		x, root *Element
+	}{
+		{new(Element), new(Element)},
+		// 1 is odd, so the even root of 1 is p - 1.
+		{new(Element).One(), minusOne},
+		{elementFromUint64(t, 4), elementFromUint64(t, 2)},
+		// 3 is odd, so the even root of 9 is p - 3.
+		{elementFromUint64(t, 9), minusThree},
+	}
+	for i, tt := range tests {
+		e := new(Element)
+		if e.Sqrt(tt.x) != 1 {
+			t.Errorf("%d: Sqrt(%x) reported a non-square", i, tt.x.Bytes())
+			continue
+		}
+		if *e != *tt.root {
+			t.Errorf("%d: Sqrt(%x) = %x, want %x", i, tt.x.Bytes(), e.Bytes(), tt.root.Bytes())
+		}
+	}
+
+	for _, r := range testElements(t) {
+		x := new(Element).Square(r)
+		e := new(Element)
+		if e.Sqrt(x) != 1 {
+			t.Errorf("Sqrt(%x) reported a non-square", x.Bytes())
+			continue
+		}
+		if *new(Element).Square(e) != *x {
+			t.Errorf("Sqrt(%x) = %x is not a root", x.Bytes(), e.Bytes())
+		}
+		if e.Bytes()[ElementLength-1]&1 != 0 {
+			t.Errorf("Sqrt(%x) = %x is not even", x.Bytes(), e.Bytes())
+		}
+
+		// p ≡ 3 mod 4, so -1 is not a square, and neither is -r².
+		if r.IsZero() == 1 {
+			continue
+		}
+		nonSquare := new(Element).Sub(new(Element), x)
+		e.Set(r)
+		if e.Sqrt(nonSquare) != 0 {
+			t.Errorf("Sqrt(%x) reported a square", nonSquare.Bytes())
+		}
+		if *e != *r {
+			t.Errorf("Sqrt(%x) modified the receiver", nonSquare.Bytes())
+		}
+	}
+
+	// The receiver may overlap with the operand.
+	x := elementFromUint64(t, 4)
+	if x.Sqrt(x) != 1 || *x != *elementFromUint64(t, 2) {
+		t.Errorf("aliased Sqrt(4) = %x", x.Bytes())
+	}
+}
+
+func TestElementCondNegate(t *testing.T) {
+	for _, x := range testElements(t) {
+		neg := new(Element).Sub(new(Element), x)
+		if got := new(Element).CondNegate(x, 1); *got != *neg {
+			t.Errorf("CondNegate(%x, 1) = %x, want %x", x.Bytes(), got.Bytes(), neg.Bytes())
+		}
+		if got := new(Element).CondNegate(x, 0); *got != *x {
+			t.Errorf("CondNegate(%x, 0) = %x, want %x", x.Bytes(), got.Bytes(), x.Bytes())
+		}
+		// The receiver may overlap with the operand.
+		if y := new(Element).Set(x); *y.CondNegate(y, 1) != *neg {
+			t.Errorf("aliased CondNegate(%x, 1) = %x", x.Bytes(), y.Bytes())
+		}
+		if y := new(Element).Set(x); *y.CondNegate(y, 0) != *x {
+			t.Errorf("aliased CondNegate(%x, 0) = %x", x.Bytes(), y.Bytes())
+		}
+	}
+}
+
+func TestElementExp(t *testing.T) {
+	// p - 2 and (p + 1) / 4.
+	pMinusTwo := decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2d")
+	sqrtExp := decodeHex("3fffffffffffffffffffffffffffffffffffffffffffffffffffffffbfffff0c")
+
+	for _, x := range testElements(t) {
+		want := new(Element).Invert(x)
+		if got := new(Element).Exp(x, pMinusTwo); *got != *want {
+			t.Errorf("%x^(p-2): got %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+		sqrtCandidate(want, x)
+		if got := new(Element).Exp(x, sqrtExp); *got != *want {
+			t.Errorf("%x^((p+1)/4): got %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+
+		one := new(Element).One()
+		if got := new(Element).Exp(x, nil); *got != *one {
+			t.Errorf("%x^(): got %x, want 1", x.Bytes(), got.Bytes())
+		}
+		if got := new(Element).Exp(x, []byte{0, 0}); *got != *one {
+			t.Errorf("%x^0: got %x, want 1", x.Bytes(), got.Bytes())
+		}
+		want = new(Element).Mul(x, x)
+		want.Mul(want, x)
+		if got := new(Element).Exp(x, []byte{0, 3}); *got != *want {
+			t.Errorf("%x^3: got %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+		// The receiver may overlap with the base.
+		if y := new(Element).Set(x); *y.Exp(y, []byte{3}) != *want {
+			t.Errorf("aliased %x^3 = %x", x.Bytes(), y.Bytes())
+		}
+	}
+}
+
+func TestExpChain(t *testing.T) {
+	// The chains must only read registers that were already written, and must
+	// match the operation counts of the addition chains they implement.
+	for _, tt := range []struct {
+		name     string
+		chain    []expStep
+		sq, muls int
+	}{
+		{"invert", invertChain, 255, 15},
+		{"sqrt", sqrtChain, 253, 13},
+	} {
+		written := map[uint8]bool{0: true}
+		sq, muls := 0, 0
+		for i, s := range tt.chain {
+			if s.dst >= maxExpRegisters || !written[s.src] || (s.mul != noMul && !written[s.mul]) {
+				t.Errorf("%s: invalid step %d: %+v", tt.name, i, s)
+			}
+			written[s.dst] = true
+			sq += int(s.sq)
+			if s.mul != noMul {
+				muls++
+			}
+		}
+		if sq != tt.sq || muls != tt.muls {
+			t.Errorf("%s: %d squarings and %d multiplications, want %d and %d", tt.name, sq, muls, tt.sq, tt.muls)
+		}
+	}
+
+	pMinusTwo := decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2d")
+	sqrtExp := decodeHex("3fffffffffffffffffffffffffffffffffffffffffffffffffffffffbfffff0c")
+	for i := 0; i < 1000; i++ {
+		x := randomElement(t)
+		if got, want := new(Element).Invert(x), new(Element).Exp(x, pMinusTwo); *got != *want {
+			t.Fatalf("Invert(%x) = %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+		got, want := new(Element), new(Element).Exp(x, sqrtExp)
+		if sqrtCandidate(got, x); *got != *want {
+			t.Fatalf("sqrtCandidate(%x) = %x, want %x", x.Bytes(), got.Bytes(), want.Bytes())
+		}
+		// The output may overlap with the input.
+		if sqrtCandidate(x, x); *x != *want {
+			t.Fatalf("aliased sqrtCandidate = %x, want %x", x.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func TestInvertBatch(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 17} {
+		in := make([]*Element, n)
+		for i := range in {
+			in[i] = randomElement(t)
+		}
+		if n > 2 {
+			in[0] = new(Element)
+			in[n/2] = new(Element)
+		}
+		out := make([]*Element, n)
+		for i := range out {
+			out[i] = new(Element)
+		}
+		InvertBatch(out, in)
+		for i := range in {
+			want := new(Element).Invert(in[i])
+			if *out[i] != *want {
+				t.Errorf("n=%d: 1/%x: got %x, want %x", n, in[i].Bytes(), out[i].Bytes(), want.Bytes())
+			}
+		}
+
+		// Inverting in place gives the same results.
+		InvertBatch(in, in)
+		for i := range in {
+			if *in[i] != *out[i] {
+				t.Errorf("n=%d: in-place result %d differs", n, i)
+			}
+		}
+	}
+}
+
+func BenchmarkInvertBatch(b *testing.B) {
+	const n = 256
+	in := make([]*Element, n)
+	out := make([]*Element, n)
+	for i := range in {
+		in[i] = randomElement(b)
+		out[i] = new(Element)
+	}
+	b.Run("Invert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range in {
+				out[j].Invert(in[j])
+			}
+		}
+	})
+	b.Run("InvertBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			InvertBatch(out, in)
+		}
+	})
+}
+
+func TestElementBig(t *testing.T) {
+	p, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(p, big.NewInt(1)),
+		new(big.Int).Rsh(p, 1),
+	} {
+		e, err := new(Element).SetBig(v)
+		if err != nil {
+			t.Errorf("SetBig(%x): %v", v, err)
+			continue
+		}
+		if want, _ := new(Element).SetBytes(v.FillBytes(make([]byte, ElementLength))); *e != *want {
+			t.Errorf("SetBig(%x) = %x", v, e.Bytes())
+		}
+		if got := e.Big(); got.Cmp(v) != 0 {
+			t.Errorf("SetBig(%x).Big() = %x", v, got)
+		}
+	}
+
+	for _, v := range []*big.Int{
+		p,
+		new(big.Int).Add(p, big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(1), 256),
+		big.NewInt(-1),
+	} {
+		e := new(Element).One()
+		if _, err := e.SetBig(v); err == nil {
+			t.Errorf("SetBig(%x): expected error", v)
+		}
+		if *e != *new(Element).One() {
+			t.Errorf("SetBig(%x) modified the receiver", v)
+		}
+	}
+}
+
+func TestElementMarshalBinary(t *testing.T) {
+	type record struct {
+		X Element
+		Y *Element
+	}
+	for _, x := range testElements(t) {
+		var buf bytes.Buffer
+		in := record{X: *x, Y: new(Element).Square(x)}
+		if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+			t.Fatal(err)
+		}
+		var out record
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if out.X != in.X || *out.Y != *in.Y {
+			t.Errorf("gob round-trip of %x: got %x, %x", x.Bytes(), out.X.Bytes(), out.Y.Bytes())
+		}
+	}
+
+	for _, b := range [][]byte{
+		decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		bytes.Repeat([]byte{0xff}, ElementLength),
+		make([]byte, ElementLength-1),
+	} {
+		if err := new(Element).UnmarshalBinary(b); err == nil {
+			t.Errorf("UnmarshalBinary(%x): expected error", b)
+		}
+	}
+}
+
+func TestIsOdd(t *testing.T) {
+	for _, e := range testElements(t) {
+		want := int(e.Bytes()[ElementLength-1] & 1)
+		if got := e.IsOdd(); got != want {
+			t.Errorf("IsOdd(%x) = %d, want %d", e.Bytes(), got, want)
+		}
+	}
+}
+
+func BenchmarkIsOdd(b *testing.B) {
+	e := new(Element).One()
+	e.Add(e, e)
+	b.Run("IsOdd", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e.IsOdd()
+		}
+	})
+	b.Run("Bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = e.Bytes()[ElementLength-1] & 1
+		}
+	})
+}
+
+func TestElementEqualIsZero(t *testing.T) {
+	elements := testElements(t)
+	// Limbs not lower than p, which can only be set directly, compare like
+	// their encoding: p is zero, and p + 1 is the Montgomery limbs of 1.
+	p := Element(fieldPrime)
+	pPlusOne := Element(fieldPrime)
+	pPlusOne[0]++
+	one := Element{1}
+	elements = append(elements, &p, &pPlusOne, &one)
+	for _, x := range elements {
+		want := 0
+		if bytes.Equal(x.Bytes(), make([]byte, ElementLength)) {
+			want = 1
+		}
+		if got := x.IsZero(); got != want {
+			t.Errorf("IsZero(%x) = %d, want %d", *x, got, want)
+		}
+		for _, y := range elements {
+			want := 0
+			if bytes.Equal(x.Bytes(), y.Bytes()) {
+				want = 1
+			}
+			if got := x.Equal(y); got != want {
+				t.Errorf("Equal(%x, %x) = %d, want %d", *x, *y, got, want)
+			}
+		}
+	}
+
+	x, y := randomElement(t), randomElement(t)
+	if n := testing.AllocsPerRun(10, func() { x.Equal(y); x.IsZero() }); n != 0 {
+		t.Errorf("Equal and IsZero allocate %v times", n)
+	}
+}
+
+func TestElementReduce(t *testing.T) {
+	ones := ^uint64(0)
+	tests := []struct {
+		in, want Element
+	}{
+		{Element{}, Element{}},
+		{Element{fieldPrime[0] - 1, ones, ones, ones}, Element{fieldPrime[0] - 1, ones, ones, ones}},
+		{Element(fieldPrime), Element{}},
+		{Element{fieldPrime[0] + 1, ones, ones, ones}, Element{1}},
+		// 2^256 - 1 = p + 2^32 + 976
+		{Element{ones, ones, ones, ones}, Element{0x1000003d0}},
+		// A borrow across the limbs: p - 2^64 is lower than p.
+		{Element{fieldPrime[0], ones - 1, ones, ones}, Element{fieldPrime[0], ones - 1, ones, ones}},
+	}
+	for _, tt := range tests {
+		e := tt.in
+		if got := e.isReduced(); got != (tt.in == tt.want) {
+			t.Errorf("%x: isReduced() = %v", tt.in, got)
+		}
+		if e.reduce(); e != tt.want || !e.isReduced() {
+			t.Errorf("%x: reduce() = %x, want %x", tt.in, e, tt.want)
+		}
+	}
+
+	// Every operation must return reduced limbs.
+	elements := testElements(t)
+	for _, x := range elements {
+		for _, y := range elements {
+			for name, e := range map[string]*Element{
+				"Add":    new(Element).Add(x, y),
+				"Sub":    new(Element).Sub(x, y),
+				"Mul":    new(Element).Mul(x, y),
+				"Square": new(Element).Square(x),
+				"Double": new(Element).Double(x),
+				"Halve":  new(Element).Halve(x),
+				"MulInt": new(Element).MulInt(x, y[0]),
+				"Invert": new(Element).Invert(x),
+			} {
+				if !e.isReduced() {
+					t.Errorf("%s(%x, %x) = %x is not reduced", name, *x, *y, *e)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkElementEqual(b *testing.B) {
+	x, y := randomElement(b), randomElement(b)
+	b.Run("Equal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.Equal(y)
+		}
+	})
+	b.Run("IsZero", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			x.IsZero()
+		}
+	})
+	b.Run("Bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			subtle.ConstantTimeCompare(x.Bytes(), y.Bytes())
+		}
+	})
+}
+
+func TestCmp(t *testing.T) {
+	zero := new(Element)
+	one := new(Element).One()
+	minusOne, err := new(Element).SetBytes(pMinusOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ordered := []*Element{zero, one, elementFromUint64(t, 2), elementFromUint64(t, 1<<63), minusOne}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.Cmp(b); got != want {
+				t.Errorf("Cmp(%x, %x) = %d, want %d", a.Bytes(), b.Bytes(), got, want)
+			}
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		a, b := randomElement(t), randomElement(t)
+		want := new(big.Int).SetBytes(a.Bytes()).Cmp(new(big.Int).SetBytes(b.Bytes()))
+		if got := a.Cmp(b); got != want {
+			t.Errorf("Cmp(%x, %x) = %d, want %d", a.Bytes(), b.Bytes(), got, want)
+		}
+		if a.Cmp(a) != 0 {
+			t.Errorf("Cmp(%x, itself) != 0", a.Bytes())
+		}
+	}
+}
+
+func TestSetBytesNonCanonical(t *testing.T) {
+	if _, err := new(Element).SetBytes(pMinusOne); err != nil {
+		t.Errorf("SetBytes(p - 1): %v", err)
+	}
+	for _, s := range []string{
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", // p
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc30", // p + 1
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffff00000000",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	} {
+		if _, err := new(Element).SetBytes(decodeHex(s)); err == nil {
+			t.Errorf("SetBytes(%s) succeeded", s)
+		}
+	}
+}
+
+func TestElementString(t *testing.T) {
+	one := new(Element).One()
+	minusOne, err := new(Element).SetBytes(pMinusOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		format string
+		e      *Element
+		want   string
+	}{
+		{"%v", new(Element), "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"%v", one, "0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{"%s", minusOne, "0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e"},
+		{"%x", new(Element), "0000000000000000000000000000000000000000000000000000000000000000"},
+		{"%x", one, "0000000000000000000000000000000000000000000000000000000000000001"},
+		{"%x", minusOne, "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e"},
+		{"%X", minusOne, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2E"},
+		{"%#x", one, "0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{"%#X", minusOne, "0XFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2E"},
+		{"%d", one, "%!d(*secp256k1.Element=0x0000000000000000000000000000000000000000000000000000000000000001)"},
+	} {
+		if got := fmt.Sprintf(tt.format, tt.e); got != tt.want {
+			t.Errorf("Sprintf(%q, %s) = %q, want %q", tt.format, tt.e, got, tt.want)
+		}
+	}
+	if got, want := minusOne.String(), "0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestElementBytesLE(t *testing.T) {
+	reverse := func(b []byte) []byte {
+		r := make([]byte, len(b))
+		for i := range b {
+			r[len(b)-1-i] = b[i]
+		}
+		return r
+	}
+	for _, x := range testElements(t) {
+		b := x.Bytes()
+		if got := x.BytesLE(); !bytes.Equal(got, reverse(b)) {
+			t.Errorf("BytesLE() = %x, want %x", got, reverse(b))
+		}
+		got, err := new(Element).SetBytesLE(reverse(b))
+		if err != nil {
+			t.Fatalf("SetBytesLE(%x): %v", reverse(b), err)
+		}
+		want, err := new(Element).SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != *want {
+			t.Errorf("SetBytesLE(%x) = %x, want %x", reverse(b), got.Bytes(), want.Bytes())
+		}
+	}
+
+	for _, v := range [][]byte{
+		reverse(decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")), // p
+		bytes.Repeat([]byte{0xff}, ElementLength),
+		make([]byte, ElementLength-1),
+		make([]byte, ElementLength+1),
+	} {
+		e := new(Element).One()
+		if _, err := e.SetBytesLE(v); err == nil {
+			t.Errorf("SetBytesLE(%x): expected error", v)
+		}
+		if *e != *new(Element).One() {
+			t.Errorf("SetBytesLE(%x): receiver modified on error", v)
+		}
+	}
+}
+
+func TestSetBytesCanonical(t *testing.T) {
+	for _, tt := range []struct {
+		v, want string
+		reduced bool
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", "0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e", "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e", false}, // p - 1
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", "0000000000000000000000000000000000000000000000000000000000000000", true},  // p
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc30", "0000000000000000000000000000000000000000000000000000000000000001", true},  // p + 1
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "00000000000000000000000000000000000000000000000000000001000003d0", true},
+	} {
+		e := new(Element)
+		reduced, err := e.SetBytesCanonical(decodeHex(tt.v))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.v, err)
+		}
+		if reduced != tt.reduced || !bytes.Equal(e.Bytes(), decodeHex(tt.want)) {
+			t.Errorf("%s: got %x, %v, want %s, %v", tt.v, e.Bytes(), reduced, tt.want, tt.reduced)
+		}
+		// SetBytes keeps rejecting non-canonical encodings.
+		if _, err := new(Element).SetBytes(decodeHex(tt.v)); (err != nil) != tt.reduced {
+			t.Errorf("%s: SetBytes error = %v", tt.v, err)
+		}
+	}
+
+	for _, v := range [][]byte{nil, make([]byte, 31), make([]byte, 33)} {
+		e := new(Element).One()
+		if _, err := e.SetBytesCanonical(v); err == nil {
+			t.Errorf("%x: expected error", v)
+		}
+		if *e != *new(Element).One() {
+			t.Errorf("%x: receiver modified on error", v)
+		}
+	}
+}
+
+func TestMulAdd(t *testing.T) {
+	for _, a := range testElements(t) {
+		b, c := randomElement(t), randomElement(t)
+		want := new(Element).Mul(a, b)
+		want.Add(want, c)
+		if got := new(Element).MulAdd(a, b, c); got.Equal(want) != 1 {
+			t.Errorf("MulAdd(%v, %v, %v) = %v, want %v", a, b, c, got, want)
+		}
+		if got := new(Element).Set(c); got.MulAdd(a, b, got).Equal(want) != 1 {
+			t.Errorf("MulAdd with e aliasing c = %v, want %v", got, want)
+		}
+		if got := new(Element).Set(a); got.MulAdd(got, b, c).Equal(want) != 1 {
+			t.Errorf("MulAdd with e aliasing a = %v, want %v", got, want)
+		}
+		sq := new(Element).Square(a)
+		if got := new(Element).Set(a); got.MulAdd(got, got, got).Equal(sq.Add(sq, a)) != 1 {
+			t.Errorf("MulAdd(a, a, a) = %v, want %v", got, sq)
+		}
+	}
+}
+
+// limbTestElements returns edge cases for the limbs of the Montgomery
+// representation, followed by random elements. It is used to compare the
+// assembly implementations of Mul and Square with the portable ones.
+func limbTestElements(t testing.TB) []*Element {
+	const p0 = 0xfffffffefffffc2f
+	elements := []*Element{
+		{0, 0, 0, 0},
+		{1, 0, 0, 0},
+		{2, 0, 0, 0},
+		{p0 - 1, 1<<64 - 1, 1<<64 - 1, 1<<64 - 1}, // p - 1
+		{p0 - 2, 1<<64 - 1, 1<<64 - 1, 1<<64 - 1}, // p - 2
+		{0, 0, 0, 1 << 63},
+		{0, 0, 0, 1<<64 - 1},
+		{1<<64 - 1, 1<<64 - 1, 1<<64 - 1, 1<<63 - 1},
+		{1<<64 - 1, 0, 1<<64 - 1, 0},
+		{0x1000003d1, 0, 0, 0},
+	}
+	for i := 0; i < 1000; i++ {
+		elements = append(elements, randomElement(t))
+	}
+	return elements
+}
+
+// FuzzMul compares Mul and Square, which may be implemented in assembly, with
+// the portable fiat-crypto implementations.
+func FuzzMul(f *testing.F) {
+	f.Add(make([]byte, ElementLength), make([]byte, ElementLength))
+	f.Add(pMinusOne, pMinusOne)
+	f.Add(bytes.Repeat([]byte{0xff}, ElementLength), bytes.Repeat([]byte{0x80}, ElementLength))
+	for i := 0; i < 8; i++ {
+		f.Add(randomElement(f).Bytes(), randomElement(f).Bytes())
+	}
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		// Fill the limbs directly, to reach every Montgomery representation.
+		var x, y Element
+		for i := 0; i < 4 && 8*i+8 <= len(a); i++ {
+			x[i] = binary.LittleEndian.Uint64(a[8*i:])
+		}
+		for i := 0; i < 4 && 8*i+8 <= len(b); i++ {
+			y[i] = binary.LittleEndian.Uint64(b[8*i:])
+		}
+		if cmpLimbs((*[4]uint64)(&x), &fieldPrime) >= 0 || cmpLimbs((*[4]uint64)(&y), &fieldPrime) >= 0 {
+			return
+		}
+
+		got := new(Element).Mul(&x, &y)
+		want := new(Element).mulGeneric(&x, &y)
+		if *got != *want {
+			t.Errorf("Mul(%x, %x) = %x, want %x", x, y, *got, *want)
+		}
+		got.Square(&x)
+		want.squareGeneric(&x)
+		if *got != *want {
+			t.Errorf("Square(%x) = %x, want %x", x, *got, *want)
+		}
+	})
+}
+
+func FuzzElementSetBytes(f *testing.F) {
+	f.Add(make([]byte, ElementLength))
+	f.Add(append(make([]byte, ElementLength-1), 1))
+	f.Add(pMinusOne)
+	f.Add(P())
+	f.Add(bytes.Repeat([]byte{0xff}, ElementLength))
+	f.Add(make([]byte, ElementLength-1))
+	f.Add(make([]byte, ElementLength+1))
+	f.Add(randomElement(f).Bytes())
+	f.Fuzz(func(t *testing.T, b []byte) {
+		e := new(Element).One()
+		if _, err := e.SetBytes(b); err != nil {
+			if e.Equal(new(Element).One()) != 1 {
+				t.Errorf("SetBytes(%x) failed but modified the receiver", b)
+			}
+			if len(b) == ElementLength && bytes.Compare(b, P()) < 0 {
+				t.Errorf("SetBytes(%x) rejected a canonical encoding: %v", b, err)
+			}
+			return
+		}
+		if got := e.Bytes(); !bytes.Equal(got, b) {
+			t.Errorf("SetBytes(%x).Bytes() = %x", b, got)
+		}
+	})
+}
@@ -30,14 +30,14 @@ func S256() elliptic.Curve {
 
 func initS256() {
 	s256.params = &elliptic.CurveParams{
-		Name:    "P-256",
+		Name:    "secp256k1",
 		BitSize: 256,
-		// FIPS 186-4, section D.1.2.3
-		P:  bigFromDecimal("115792089210356248762697446949407573530086143415290314195533631308867097853951"),
-		N:  bigFromDecimal("115792089210356248762697446949407573529996955224135760342422259061068512044369"),
-		B:  bigFromHex("5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b"),
-		Gx: bigFromHex("6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296"),
-		Gy: bigFromHex("4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5"),
+		// SEC 2, Version 2.0, Section 2.4.1
+		P:  bigFromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		N:  bigFromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+		B:  big.NewInt(7),
+		Gx: bigFromHex("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		Gy: bigFromHex("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
 	}
 }
 
@@ -138,6 +138,15 @@ func (curve *nistCurve[Point]) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
 
 // normalizeScalar brings the scalar within the byte size of the order of the
 // curve, as expected by the nistec scalar multiplication functions.
+//
+// Scalars longer than the order are reduced modulo N with math/big, which is
+// not constant time. That is acceptable because such scalars are not produced
+// by this package or crypto/ecdsa, and constant-time callers should pass
+// scalars of the right length. Scalars of the right length are passed through
+// unreduced, as the scalar multiplication accepts any 32-byte value.
+//
+// A scalar that is a multiple of N, including zero, is a valid input, and
+// multiplying by it produces the point at infinity.
 func (curve *nistCurve[Point]) normalizeScalar(scalar []byte) []byte {
 	byteSize := (curve.params.N.BitLen() + 7) / 8
 	if len(scalar) == byteSize {
@@ -151,6 +160,8 @@ func (curve *nistCurve[Point]) normalizeScalar(scalar []byte) []byte {
 	return s.FillBytes(out)
 }
 
+// ScalarMult returns [scalar](Bx, By). If scalar is zero or a multiple of N,
+// the result is the point at infinity, returned as (0, 0) by convention.
 func (curve *nistCurve[Point]) ScalarMult(Bx, By *big.Int, scalar []byte) (*big.Int, *big.Int) {
 	p, err := curve.pointFromAffine(Bx, By)
 	if err != nil {
@@ -164,6 +175,9 @@ func (curve *nistCurve[Point]) ScalarMult(Bx, By *big.Int, scalar []byte) (*big.
 	return curve.pointToAffine(p)
 }
 
+// ScalarBaseMult returns [scalar]G, where G is the generator. If scalar is zero
+// or a multiple of N, the result is the point at infinity, returned as (0, 0)
+// by convention.
 func (curve *nistCurve[Point]) ScalarBaseMult(scalar []byte) (*big.Int, *big.Int) {
 	scalar = curve.normalizeScalar(scalar)
 	p, err := curve.newPoint().ScalarBaseMult(scalar)
@@ -222,12 +236,47 @@ func (curve *nistCurve[Point]) UnmarshalCompressed(data []byte) (x, y *big.Int)
 	return curve.pointToAffine(p)
 }
 
-func bigFromDecimal(s string) *big.Int {
-	b, ok := new(big.Int).SetString(s, 10)
-	if !ok {
-		panic("crypto/elliptic: internal error: invalid encoding")
+// UnmarshalAny converts a point, serialized in either the uncompressed or the
+// compressed form, into an x, y pair. It dispatches on the type byte to
+// Unmarshal or UnmarshalCompressed. On error, including for the encoding of
+// the point at infinity, x = nil.
+func (curve *nistCurve[Point]) UnmarshalAny(data []byte) (x, y *big.Int) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	switch data[0] {
+	case 4:
+		return curve.Unmarshal(data)
+	case 2, 3:
+		return curve.UnmarshalCompressed(data)
+	default:
+		return nil, nil
+	}
+}
+
+// UnmarshalAny converts a point, serialized in either the uncompressed or the
+// compressed form, into an x, y pair. It is the package-level entry point to
+// the UnmarshalAny method of S256, which can't be reached through the
+// elliptic.Curve interface. For other curves, it dispatches on the type byte
+// to the Unmarshal and UnmarshalCompressed functions of crypto/elliptic. On
+// error, including for the encoding of the point at infinity, x = nil.
+func UnmarshalAny(curve elliptic.Curve, data []byte) (x, y *big.Int) {
+	if c, ok := curve.(interface {
+		UnmarshalAny([]byte) (*big.Int, *big.Int)
+	}); ok {
+		return c.UnmarshalAny(data)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	switch data[0] {
+	case 4:
+		return elliptic.Unmarshal(curve, data)
+	case 2, 3:
+		return elliptic.UnmarshalCompressed(curve, data)
+	default:
+		return nil, nil
 	}
-	return b
 }
 
 func bigFromHex(s string) *big.Int {
@@ -5,6 +5,7 @@
 package elliptic
 
 import (
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 	"testing"
@@ -70,3 +71,125 @@ func BenchmarkBaseMult(b *testing.B) {
 		s256.ScalarBaseMult(k.Bytes())
 	}
 }
+
+func TestParams(t *testing.T) {
+	// The published secp256k1 domain parameters, from SEC 2, Version 2.0,
+	// Section 2.4.1, with P and N in decimal as a cross-check.
+	params := S256().Params()
+	if params.Name != "secp256k1" {
+		t.Errorf("Name = %q, want secp256k1", params.Name)
+	}
+	if params.BitSize != 256 {
+		t.Errorf("BitSize = %d, want 256", params.BitSize)
+	}
+	tests := []struct {
+		name string
+		got  *big.Int
+		want string
+		base int
+	}{
+		{"P", params.P, "115792089237316195423570985008687907853269984665640564039457584007908834671663", 10},
+		{"N", params.N, "115792089237316195423570985008687907852837564279074904382605163141518161494337", 10},
+		{"B", params.B, "7", 10},
+		{"Gx", params.Gx, "79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16},
+		{"Gy", params.Gy, "483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16},
+	}
+	for _, tt := range tests {
+		want, ok := new(big.Int).SetString(tt.want, tt.base)
+		if !ok {
+			t.Fatalf("bad constant for %s", tt.name)
+		}
+		if tt.got.Cmp(want) != 0 {
+			t.Errorf("%s = %X, want %X", tt.name, tt.got, want)
+		}
+	}
+	if !S256().IsOnCurve(params.Gx, params.Gy) {
+		t.Error("generator is not on the curve")
+	}
+	if x, y := S256().ScalarBaseMult([]byte{1}); x.Cmp(params.Gx) != 0 || y.Cmp(params.Gy) != 0 {
+		t.Errorf("ScalarBaseMult(1) = (%X, %X), want the generator", x, y)
+	}
+}
+
+func TestZeroEquivalentScalars(t *testing.T) {
+	s256 := S256()
+	params := s256.Params()
+	twoN := new(big.Int).Lsh(params.N, 1)
+	scalars := map[string][]byte{
+		"empty":     {},
+		"zero":      make([]byte, 32),
+		"long zero": make([]byte, 40),
+		"N":         params.N.Bytes(),
+		"2N":        twoN.Bytes(),
+		"padded N":  params.N.FillBytes(make([]byte, 40)),
+	}
+	for name, k := range scalars {
+		if x, y := s256.ScalarBaseMult(k); x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("%s: ScalarBaseMult = (%X, %X), want (0, 0)", name, x, y)
+		}
+		if x, y := s256.ScalarMult(params.Gx, params.Gy, k); x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("%s: ScalarMult = (%X, %X), want (0, 0)", name, x, y)
+		}
+	}
+
+	// 2N + 1 is equivalent to one.
+	k := new(big.Int).Add(twoN, big.NewInt(1)).Bytes()
+	if x, y := s256.ScalarMult(params.Gx, params.Gy, k); x.Cmp(params.Gx) != 0 || y.Cmp(params.Gy) != 0 {
+		t.Errorf("ScalarMult(2N + 1) = (%X, %X), want the generator", x, y)
+	}
+}
+
+func TestUnmarshalAny(t *testing.T) {
+	curve := S256().(*s256Curve)
+	params := curve.Params()
+	x, y := curve.ScalarBaseMult([]byte{42})
+	uncompressed := elliptic.Marshal(curve, x, y)
+	compressed := elliptic.MarshalCompressed(curve, x, y)
+	negY := new(big.Int).Sub(params.P, y)
+	negCompressed := elliptic.MarshalCompressed(curve, x, negY)
+
+	for _, tt := range []struct {
+		name         string
+		data         []byte
+		wantX, wantY *big.Int
+	}{
+		{"uncompressed", uncompressed, x, y},
+		{"compressed", compressed, x, y},
+		{"compressed negated", negCompressed, x, negY},
+		{"infinity", []byte{0}, nil, nil},
+		{"empty", nil, nil, nil},
+		{"hybrid", append([]byte{6 | byte(y.Bit(0))}, uncompressed[1:]...), nil, nil},
+		{"truncated", uncompressed[:64], nil, nil},
+		{"off curve", append(uncompressed[:64:64], uncompressed[64]^1), nil, nil},
+		{"garbage", []byte("not a point"), nil, nil},
+	} {
+		gotX, gotY := curve.UnmarshalAny(tt.data)
+		checkUnmarshalAny(t, "method "+tt.name, gotX, gotY, tt.wantX, tt.wantY)
+		gotX, gotY = UnmarshalAny(S256(), tt.data)
+		checkUnmarshalAny(t, tt.name, gotX, gotY, tt.wantX, tt.wantY)
+	}
+
+	// Other curves go through crypto/elliptic.
+	p256 := elliptic.P256()
+	x, y = p256.ScalarBaseMult([]byte{42})
+	for _, data := range [][]byte{elliptic.Marshal(p256, x, y), elliptic.MarshalCompressed(p256, x, y)} {
+		gotX, gotY := UnmarshalAny(p256, data)
+		checkUnmarshalAny(t, fmt.Sprintf("P-256 %x", data[:1]), gotX, gotY, x, y)
+	}
+	if gotX, _ := UnmarshalAny(p256, []byte{0}); gotX != nil {
+		t.Errorf("P-256: accepted the point at infinity")
+	}
+}
+
+func checkUnmarshalAny(t *testing.T, name string, gotX, gotY, wantX, wantY *big.Int) {
+	t.Helper()
+	if wantX == nil {
+		if gotX != nil {
+			t.Errorf("%s: got (%X, %X), want nil", name, gotX, gotY)
+		}
+		return
+	}
+	if gotX == nil || gotX.Cmp(wantX) != 0 || gotY.Cmp(wantY) != 0 {
+		t.Errorf("%s: got (%X, %X), want (%X, %X)", name, gotX, gotY, wantX, wantY)
+	}
+}
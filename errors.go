@@ -0,0 +1,45 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import "errors"
+
+// Sentinel errors for the failure modes of the decoding functions, such as
+// SetBytes and SetBytesXOnly. The returned errors keep their own descriptive
+// messages, and match these with errors.Is.
+var (
+	// ErrInvalidLength is returned for inputs of the wrong length.
+	ErrInvalidLength = errors.New("invalid secp256k1 encoding length")
+	// ErrNotOnCurve is returned for coordinates that are not those of a point
+	// on the curve.
+	ErrNotOnCurve = errors.New("secp256k1 point not on curve")
+	// ErrNonCanonical is returned for field elements encoded as values not
+	// lower than p.
+	ErrNonCanonical = errors.New("non-canonical secp256k1 field element encoding")
+	// ErrInfinityNotAllowed is returned where the point at infinity can't be
+	// encoded or isn't a valid value.
+	ErrInfinityNotAllowed = errors.New("secp256k1 point at infinity not allowed")
+)
+
+// encodingError is an error with a descriptive message that matches one of
+// the sentinel errors.
+type encodingError struct {
+	msg  string
+	kind error
+}
+
+func (e *encodingError) Error() string { return e.msg }
+func (e *encodingError) Unwrap() error { return e.kind }
+
+var (
+	errElementLength        = &encodingError{"invalid Element encoding", ErrInvalidLength}
+	errElementNonCanonical  = &encodingError{"invalid Element encoding", ErrNonCanonical}
+	errPointNotOnCurve      = &encodingError{"secp256k1 point not on curve", ErrNotOnCurve}
+	errPointLength          = &encodingError{"invalid secp256k1 point encoding", ErrInvalidLength}
+	errCompressedNotOnCurve = &encodingError{"invalid secp256k1 compressed point encoding", ErrNotOnCurve}
+	errXOnlyLength          = &encodingError{"invalid secp256k1 x-only point encoding", ErrInvalidLength}
+	errXOnlyNotOnCurve      = &encodingError{"invalid secp256k1 x-only point encoding", ErrNotOnCurve}
+	errInfinity             = &encodingError{"secp256k1 point is the point at infinity", ErrInfinityNotAllowed}
+)
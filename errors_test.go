@@ -0,0 +1,76 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	g := NewGenerator().Bytes()
+	gx := g[1 : 1+ElementLength]
+	pBytes := P()
+	offCurve := append(g[:64:64], g[64]^1)
+	// 0x05 is not the X coordinate of any point.
+	noX := append(make([]byte, 31), 5)
+
+	for _, tt := range []struct {
+		name    string
+		f       func() error
+		want    error
+		message string
+	}{
+		{"SetBytes empty", setBytes(nil), ErrInvalidLength, "invalid secp256k1 point encoding"},
+		{"SetBytes short", setBytes(g[:64]), ErrInvalidLength, "invalid secp256k1 point encoding"},
+		{"SetBytes off curve", setBytes(offCurve), ErrNotOnCurve, "secp256k1 point not on curve"},
+		{"SetBytes compressed no Y", setBytes(append([]byte{2}, noX...)), ErrNotOnCurve, "invalid secp256k1 compressed point encoding"},
+		{"SetBytes X = p", setBytes(append([]byte{2}, pBytes...)), ErrNonCanonical, "invalid Element encoding"},
+		{"SetBytes Y = p", setBytes(append(append([]byte{4}, gx...), pBytes...)), ErrNonCanonical, "invalid Element encoding"},
+		{"SetBytesXOnly short", setBytesXOnly(gx[:31]), ErrInvalidLength, "invalid secp256k1 x-only point encoding"},
+		{"SetBytesXOnly no Y", setBytesXOnly(noX), ErrNotOnCurve, "invalid secp256k1 x-only point encoding"},
+		{"SetBytesXOnly X = p", setBytesXOnly(pBytes), ErrNonCanonical, "invalid Element encoding"},
+		{"ValidatePublicKeyBytes off curve", func() error { return ValidatePublicKeyBytes(offCurve) }, ErrNotOnCurve, "secp256k1 point not on curve"},
+		{"Element.SetBytes short", func() error { _, err := new(Element).SetBytes(gx[:31]); return err }, ErrInvalidLength, "invalid Element encoding"},
+		{"Element.SetBytes p", func() error { _, err := new(Element).SetBytes(pBytes); return err }, ErrNonCanonical, "invalid Element encoding"},
+		{"BytesX infinity", func() error { _, err := NewPoint().BytesX(); return err }, ErrInfinityNotAllowed, "secp256k1 point is the point at infinity"},
+		{"BytesFixed infinity", func() error { _, err := NewPoint().BytesFixed(); return err }, ErrInfinityNotAllowed, "secp256k1 point is the point at infinity"},
+	} {
+		err := tt.f()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err.Error() != tt.message {
+			t.Errorf("%s: message %q, want %q", tt.name, err, tt.message)
+		}
+		for _, other := range []error{ErrInvalidLength, ErrNotOnCurve, ErrNonCanonical, ErrInfinityNotAllowed} {
+			if other != tt.want && errors.Is(err, other) {
+				t.Errorf("%s: %v also matches %v", tt.name, err, other)
+			}
+		}
+	}
+
+	// A bad type byte with a valid length matches none of the sentinels.
+	err := setBytes(append([]byte{5}, bytes.Repeat([]byte{1}, 32)...))()
+	if err == nil || errors.Is(err, ErrInvalidLength) {
+		t.Errorf("bad type byte: got %v", err)
+	}
+}
+
+func setBytes(b []byte) func() error {
+	return func() error {
+		_, err := NewPoint().SetBytes(b)
+		return err
+	}
+}
+
+func setBytesXOnly(b []byte) func() error {
+	return func() error {
+		_, err := NewPoint().SetBytesXOnly(b)
+		return err
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package eth implements the derivation of Ethereum addresses from secp256k1
+// public keys.
+package eth
+
+import (
+	"github.com/wdvxdr1123/secp256k1"
+	"github.com/wdvxdr1123/secp256k1/internal/keccak"
+)
+
+// AddressLength is the length of an Ethereum address in bytes.
+const AddressLength = 20
+
+// Address returns the Ethereum address of the public key pub, which is the
+// last 20 bytes of the Keccak-256 hash of the 64-byte X ‖ Y encoding of pub.
+//
+// Address panics if pub is the point at infinity, which is not a valid public
+// key and has no address.
+func Address(pub *secp256k1.Point) [AddressLength]byte {
+	xy, err := pub.BytesRaw64()
+	if err != nil {
+		panic("eth: address of the point at infinity")
+	}
+	h := keccak.Sum256(xy)
+	var addr [AddressLength]byte
+	copy(addr[:], h[keccak.Size-AddressLength:])
+	return addr
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package eth
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		priv, addr string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001",
+			"0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{"0000000000000000000000000000000000000000000000000000000000000002",
+			"0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF"},
+		{"0000000000000000000000000000000000000000000000000000000000000003",
+			"0x6813Eb9362372EEF6200f3b1dbC3f819671cBA69"},
+		// The first account of the Hardhat and Anvil development networks.
+		{"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+			"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+	}
+	for _, tt := range tests {
+		priv, err := hex.DecodeString(tt.priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := secp256k1.NewPoint().ScalarBaseMult(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := Address(pub)
+		if got := "0x" + hex.EncodeToString(addr[:]); got != strings.ToLower(tt.addr) {
+			t.Errorf("Address(%s * G) = %s, want %s", tt.priv, got, tt.addr)
+		}
+	}
+}
+
+func TestAddressInfinity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Address of the point at infinity didn't panic")
+		}
+	}()
+	Address(secp256k1.NewPoint())
+}
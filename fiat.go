@@ -66,8 +66,9 @@ func (e *Element) Sub(t1, t2 *Element) *Element {
 	return e
 }
 
-// Mul sets e = t1 * t2, and returns e.
-func (e *Element) Mul(t1, t2 *Element) *Element {
+// mulGeneric sets e = t1 * t2, and returns e. It is the portable
+// implementation of Mul.
+func (e *Element) mulGeneric(t1, t2 *Element) *Element {
 	x1 := t1[1]
 	x2 := t1[2]
 	x3 := t1[3]
@@ -194,8 +195,9 @@ func (e *Element) Mul(t1, t2 *Element) *Element {
 	return e
 }
 
-// Square sets e = t * t, and returns e.
-func (e *Element) Square(t *Element) *Element {
+// squareGeneric sets e = t * t, and returns e. It is the portable
+// implementation of Square.
+func (e *Element) squareGeneric(t *Element) *Element {
 	x1 := t[1]
 	x2 := t[2]
 	x3 := t[3]
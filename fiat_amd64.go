@@ -0,0 +1,50 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package secp256k1
+
+// useADX reports whether the CPU supports the BMI2 and ADX extensions, which
+// provide the MULX, ADCX, and ADOX instructions used by mulADX and squareADX.
+var useADX = hasBMI2ADX()
+
+func hasBMI2ADX() bool {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+	_, ebx, _, _ := cpuid(7, 0)
+	const bmi2, adx = 1 << 8, 1 << 19
+	return ebx&bmi2 != 0 && ebx&adx != 0
+}
+
+// cpuid executes the CPUID instruction with the given EAX and ECX inputs.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// mulADX sets out = a * b in the Montgomery domain, like mulGeneric.
+//
+//go:noescape
+func mulADX(out, a, b *Element)
+
+// squareADX sets out = a * a in the Montgomery domain, like squareGeneric.
+//
+//go:noescape
+func squareADX(out, a *Element)
+
+func mul(e, t1, t2 *Element) {
+	if useADX {
+		mulADX(e, t1, t2)
+		return
+	}
+	e.mulGeneric(t1, t2)
+}
+
+func square(e, t *Element) {
+	if useADX {
+		squareADX(e, t)
+		return
+	}
+	e.squareGeneric(t)
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+#include "textflag.h"
+
+// The multiplication and squaring compute the 512-bit product into R8, R9,
+// R10, R11, R12, R13, R14, and BX, from the least significant limb, and then
+// share the Montgomery reduction below.
+//
+// Each reduction round computes m = t₀ · -p⁻¹ mod 2⁶⁴ and adds m · p to the
+// product, which clears its lowest limb. Since p = 2²⁵⁶ - c with
+// c = 2³² + 977, that is m · 2²⁵⁶ - m · c. The low limb of m · c is equal to
+// t₀ by construction, so the round only subtracts its high limb from t₁, and
+// stores m in the cleared limb. The multiplications use MULX, which leaves the
+// flags alone, so the borrows of the four rounds form a single chain, which is
+// propagated to the top limb in DI at the end. After four rounds, the sum of the m · 2²⁵⁶ terms is added back to the
+// upper half of the product in one pass, giving a result lower than 2p, which
+// is fully reduced with a final conditional subtraction of p, computed as an
+// addition of c, and left in R12, R13, R14, and BX.
+#define REDUCE \
+	MOVQ  $0xd838091dd2253531, CX \
+	MOVQ  $0x1000003d1, SI        \
+	XORQ  DI, DI                  \
+	\
+	MOVQ  R8, DX       \
+	MULXQ CX, DX, AX   \
+	MULXQ SI, R8, AX   \
+	MOVQ  DX, R8       \
+	SUBQ  AX, R9       \
+	\
+	MOVQ  R9, DX       \
+	MULXQ CX, DX, AX   \
+	MULXQ SI, R9, AX   \
+	MOVQ  DX, R9       \
+	SBBQ  AX, R10      \
+	\
+	MOVQ  R10, DX      \
+	MULXQ CX, DX, AX   \
+	MULXQ SI, R10, AX  \
+	MOVQ  DX, R10      \
+	SBBQ  AX, R11      \
+	\
+	MOVQ  R11, DX      \
+	MULXQ CX, DX, AX   \
+	MULXQ SI, R11, AX  \
+	MOVQ  DX, R11      \
+	SBBQ  AX, R12      \
+	SBBQ  $0, R13      \
+	SBBQ  $0, R14      \
+	SBBQ  $0, BX       \
+	SBBQ  $0, DI       \
+	\
+	ADDQ  R8, R12  \
+	ADCQ  R9, R13  \
+	ADCQ  R10, R14 \
+	ADCQ  R11, BX  \
+	ADCQ  $0, DI   \
+	\
+	MOVQ  R12, R8  \
+	ADDQ  SI, R8   \
+	MOVQ  R13, R9  \
+	ADCQ  $0, R9   \
+	MOVQ  R14, R10 \
+	ADCQ  $0, R10  \
+	MOVQ  BX, R11  \
+	ADCQ  $0, R11  \
+	ADCQ  $0, DI   \
+	TESTQ DI, DI   \
+	CMOVQNE R8, R12  \
+	CMOVQNE R9, R13  \
+	CMOVQNE R10, R14 \
+	CMOVQNE R11, BX
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func mulADX(out, a, b *Element)
+TEXT ·mulADX(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DI
+
+	// a₀ · b
+	MOVQ  0(SI), DX
+	MULXQ 0(DI), R8, R9
+	MULXQ 8(DI), AX, R10
+	ADDQ  AX, R9
+	MULXQ 16(DI), AX, R11
+	ADCQ  AX, R10
+	MULXQ 24(DI), AX, R12
+	ADCQ  AX, R11
+	ADCQ  $0, R12
+
+	// a₁ · b, with the low halves on the carry chain and the high halves on
+	// the overflow chain.
+	MOVQ  8(SI), DX
+	XORQ  R13, R13
+	MULXQ 0(DI), AX, CX
+	ADCXQ AX, R9
+	ADOXQ CX, R10
+	MULXQ 8(DI), AX, CX
+	ADCXQ AX, R10
+	ADOXQ CX, R11
+	MULXQ 16(DI), AX, CX
+	ADCXQ AX, R11
+	ADOXQ CX, R12
+	MULXQ 24(DI), AX, CX
+	ADCXQ AX, R12
+	ADOXQ CX, R13
+	MOVQ  $0, AX
+	ADCXQ AX, R13
+
+	// a₂ · b
+	MOVQ  16(SI), DX
+	XORQ  R14, R14
+	MULXQ 0(DI), AX, CX
+	ADCXQ AX, R10
+	ADOXQ CX, R11
+	MULXQ 8(DI), AX, CX
+	ADCXQ AX, R11
+	ADOXQ CX, R12
+	MULXQ 16(DI), AX, CX
+	ADCXQ AX, R12
+	ADOXQ CX, R13
+	MULXQ 24(DI), AX, CX
+	ADCXQ AX, R13
+	ADOXQ CX, R14
+	MOVQ  $0, AX
+	ADCXQ AX, R14
+
+	// a₃ · b
+	MOVQ  24(SI), DX
+	XORQ  BX, BX
+	MULXQ 0(DI), AX, CX
+	ADCXQ AX, R11
+	ADOXQ CX, R12
+	MULXQ 8(DI), AX, CX
+	ADCXQ AX, R12
+	ADOXQ CX, R13
+	MULXQ 16(DI), AX, CX
+	ADCXQ AX, R13
+	ADOXQ CX, R14
+	MULXQ 24(DI), AX, CX
+	ADCXQ AX, R14
+	ADOXQ CX, BX
+	MOVQ  $0, AX
+	ADCXQ AX, BX
+
+	REDUCE
+	MOVQ out+0(FP), AX
+	MOVQ R12, 0(AX)
+	MOVQ R13, 8(AX)
+	MOVQ R14, 16(AX)
+	MOVQ BX, 24(AX)
+	RET
+
+// func squareADX(out, a *Element)
+TEXT ·squareADX(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), SI
+
+	// The cross products aᵢ · aⱼ with i < j, into limbs 1 to 6.
+	MOVQ  0(SI), DX
+	MULXQ 8(SI), R9, R10
+	MULXQ 16(SI), AX, R11
+	ADDQ  AX, R10
+	MULXQ 24(SI), AX, R12
+	ADCQ  AX, R11
+	ADCQ  $0, R12
+
+	MOVQ  8(SI), DX
+	XORQ  R13, R13
+	MULXQ 16(SI), AX, CX
+	ADCXQ AX, R11
+	ADOXQ CX, R12
+	MULXQ 24(SI), AX, CX
+	ADCXQ AX, R12
+	ADOXQ CX, R13
+	MOVQ  $0, AX
+	ADCXQ AX, R13
+
+	MOVQ  16(SI), DX
+	MULXQ 24(SI), AX, R14
+	ADDQ  AX, R13
+	ADCQ  $0, R14
+
+	// Double them, into limbs 1 to 7.
+	XORQ BX, BX
+	ADDQ R9, R9
+	ADCQ R10, R10
+	ADCQ R11, R11
+	ADCQ R12, R12
+	ADCQ R13, R13
+	ADCQ R14, R14
+	ADCQ $0, BX
+
+	// Add the squares aᵢ², into limbs 2i and 2i + 1.
+	MOVQ  0(SI), DX
+	MULXQ DX, R8, AX
+	ADDQ  AX, R9
+	MOVQ  8(SI), DX
+	MULXQ DX, AX, CX
+	ADCQ  AX, R10
+	ADCQ  CX, R11
+	MOVQ  16(SI), DX
+	MULXQ DX, AX, CX
+	ADCQ  AX, R12
+	ADCQ  CX, R13
+	MOVQ  24(SI), DX
+	MULXQ DX, AX, CX
+	ADCQ  AX, R14
+	ADCQ  CX, BX
+
+	REDUCE
+	MOVQ out+0(FP), AX
+	MOVQ R12, 0(AX)
+	MOVQ R13, 8(AX)
+	MOVQ R14, 16(AX)
+	MOVQ BX, 24(AX)
+	RET
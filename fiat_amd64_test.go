@@ -0,0 +1,71 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && gc && !purego
+
+package secp256k1
+
+import "testing"
+
+func TestMulADX(t *testing.T) {
+	if !useADX {
+		t.Skip("BMI2 and ADX are not supported by this CPU")
+	}
+	elements := limbTestElements(t)
+	for i, a := range elements {
+		for _, b := range []*Element{a, elements[(i+1)%len(elements)], elements[i%10], elements[len(elements)-1-i]} {
+			var got, want Element
+			mulADX(&got, a, b)
+			want.mulGeneric(a, b)
+			if got != want {
+				t.Fatalf("mulADX(%x, %x) = %x, want %x", *a, *b, got, want)
+			}
+		}
+
+		var got, want Element
+		squareADX(&got, a)
+		want.squareGeneric(a)
+		if got != want {
+			t.Fatalf("squareADX(%x) = %x, want %x", *a, got, want)
+		}
+	}
+
+	// The output may overlap with the inputs.
+	a, b := randomElement(t), randomElement(t)
+	want := new(Element).mulGeneric(a, b)
+	if got := new(Element).Set(a); func() bool { mulADX(got, got, b); return *got != *want }() {
+		t.Errorf("mulADX with out aliasing a = %x, want %x", *got, *want)
+	}
+	want.squareGeneric(a)
+	if got := new(Element).Set(a); func() bool { squareADX(got, got); return *got != *want }() {
+		t.Errorf("squareADX with out aliasing a = %x, want %x", *got, *want)
+	}
+}
+
+func BenchmarkMulADX(b *testing.B) {
+	if !useADX {
+		b.Skip("BMI2 and ADX are not supported by this CPU")
+	}
+	x, y := randomElement(b), randomElement(b)
+	b.Run("mulGeneric", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			x.mulGeneric(x, y)
+		}
+	})
+	b.Run("mulADX", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mulADX(x, x, y)
+		}
+	})
+	b.Run("squareGeneric", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			x.squareGeneric(x)
+		}
+	})
+	b.Run("squareADX", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			squareADX(x, x)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego && secp256k1_arm64asm
+
+// The arm64 assembly is opt-in with the secp256k1_arm64asm build tag. By
+// default, arm64 builds use the fiat-crypto code in fiat.go. TestMulARM64 and
+// FuzzMulARM64 compare the two, and run on a native arm64 runner in CI, see
+// .github/workflows/arm64.yml.
+
+package secp256k1
+
+// mulARM64 sets out = a * b in the Montgomery domain, like mulGeneric.
+//
+//go:noescape
+func mulARM64(out, a, b *Element)
+
+// squareARM64 sets out = a * a in the Montgomery domain, like squareGeneric.
+//
+//go:noescape
+func squareARM64(out, a *Element)
+
+func mul(e, t1, t2 *Element) { mulARM64(e, t1, t2) }
+
+func square(e, t *Element) { squareARM64(e, t) }
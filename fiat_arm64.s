@@ -0,0 +1,159 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego && secp256k1_arm64asm
+
+#include "textflag.h"
+
+// MUL_REDUCE computes the Montgomery product of a, in R0 to R3, and b, in R4
+// to R7, from the least significant limb, and leaves it in R12 to R15.
+//
+// The 512-bit product is accumulated into R8 to R15 one row at a time, with
+// the low halves of the row on one carry chain and the high halves on a
+// second one.
+//
+// Each reduction round computes m = t₀ · -p⁻¹ mod 2⁶⁴ and adds m · p to the
+// product, which clears its lowest limb. Since p = 2²⁵⁶ - c with
+// c = 2³² + 977, that is m · 2²⁵⁶ - m · c. The low limb of m · c is equal to
+// t₀ by construction, so the round only subtracts its high limb from t₁, and
+// stores m in the cleared limb. MUL and UMULH leave the flags alone, so the
+// borrows of the four rounds form a single chain, which is propagated to the
+// top limb in R2 at the end. The sum of the m · 2²⁵⁶ terms is then added back
+// to the upper half of the product in one pass, giving a result lower than
+// 2p, which is fully reduced with a final conditional subtraction of p,
+// computed as an addition of c.
+#define MUL_REDUCE \
+	MUL   R4, R0, R8   \
+	UMULH R4, R0, R16  \
+	MUL   R5, R0, R17  \
+	UMULH R5, R0, R19  \
+	MUL   R6, R0, R20  \
+	UMULH R6, R0, R21  \
+	MUL   R7, R0, R22  \
+	UMULH R7, R0, R12  \
+	ADDS  R16, R17, R9 \
+	ADCS  R19, R20, R10 \
+	ADCS  R21, R22, R11 \
+	ADC   ZR, R12, R12 \
+	\
+	MUL   R4, R1, R16  \
+	MUL   R5, R1, R17  \
+	MUL   R6, R1, R19  \
+	MUL   R7, R1, R20  \
+	ADDS  R16, R9, R9  \
+	ADCS  R17, R10, R10 \
+	ADCS  R19, R11, R11 \
+	ADCS  R20, R12, R12 \
+	ADC   ZR, ZR, R13  \
+	UMULH R4, R1, R16  \
+	UMULH R5, R1, R17  \
+	UMULH R6, R1, R19  \
+	UMULH R7, R1, R20  \
+	ADDS  R16, R10, R10 \
+	ADCS  R17, R11, R11 \
+	ADCS  R19, R12, R12 \
+	ADC   R20, R13, R13 \
+	\
+	MUL   R4, R2, R16  \
+	MUL   R5, R2, R17  \
+	MUL   R6, R2, R19  \
+	MUL   R7, R2, R20  \
+	ADDS  R16, R10, R10 \
+	ADCS  R17, R11, R11 \
+	ADCS  R19, R12, R12 \
+	ADCS  R20, R13, R13 \
+	ADC   ZR, ZR, R14  \
+	UMULH R4, R2, R16  \
+	UMULH R5, R2, R17  \
+	UMULH R6, R2, R19  \
+	UMULH R7, R2, R20  \
+	ADDS  R16, R11, R11 \
+	ADCS  R17, R12, R12 \
+	ADCS  R19, R13, R13 \
+	ADC   R20, R14, R14 \
+	\
+	MUL   R4, R3, R16  \
+	MUL   R5, R3, R17  \
+	MUL   R6, R3, R19  \
+	MUL   R7, R3, R20  \
+	ADDS  R16, R11, R11 \
+	ADCS  R17, R12, R12 \
+	ADCS  R19, R13, R13 \
+	ADCS  R20, R14, R14 \
+	ADC   ZR, ZR, R15  \
+	UMULH R4, R3, R16  \
+	UMULH R5, R3, R17  \
+	UMULH R6, R3, R19  \
+	UMULH R7, R3, R20  \
+	ADDS  R16, R12, R12 \
+	ADCS  R17, R13, R13 \
+	ADCS  R19, R14, R14 \
+	ADC   R20, R15, R15 \
+	\
+	MOVD  $0xd838091dd2253531, R0 \
+	MOVD  $0x1000003d1, R1 \
+	MOVD  ZR, R2       \
+	\
+	MUL   R0, R8, R8   \
+	UMULH R1, R8, R16  \
+	SUBS  R16, R9, R9  \
+	MUL   R0, R9, R9   \
+	UMULH R1, R9, R16  \
+	SBCS  R16, R10, R10 \
+	MUL   R0, R10, R10 \
+	UMULH R1, R10, R16 \
+	SBCS  R16, R11, R11 \
+	MUL   R0, R11, R11 \
+	UMULH R1, R11, R16 \
+	SBCS  R16, R12, R12 \
+	SBCS  ZR, R13, R13 \
+	SBCS  ZR, R14, R14 \
+	SBCS  ZR, R15, R15 \
+	SBC   ZR, R2, R2   \
+	\
+	ADDS  R8, R12, R12 \
+	ADCS  R9, R13, R13 \
+	ADCS  R10, R14, R14 \
+	ADCS  R11, R15, R15 \
+	ADC   ZR, R2, R2   \
+	\
+	ADDS  R1, R12, R8  \
+	ADCS  ZR, R13, R9  \
+	ADCS  ZR, R14, R10 \
+	ADCS  ZR, R15, R11 \
+	ADC   ZR, R2, R2   \
+	CMP   $0, R2       \
+	CSEL  NE, R8, R12, R12 \
+	CSEL  NE, R9, R13, R13 \
+	CSEL  NE, R10, R14, R14 \
+	CSEL  NE, R11, R15, R15
+
+// func mulARM64(out, a, b *Element)
+TEXT ·mulARM64(SB), NOSPLIT, $0-24
+	MOVD a+8(FP), R16
+	LDP  0(R16), (R0, R1)
+	LDP  16(R16), (R2, R3)
+	MOVD b+16(FP), R16
+	LDP  0(R16), (R4, R5)
+	LDP  16(R16), (R6, R7)
+	MUL_REDUCE
+	MOVD out+0(FP), R16
+	STP  (R12, R13), 0(R16)
+	STP  (R14, R15), 16(R16)
+	RET
+
+// func squareARM64(out, a *Element)
+TEXT ·squareARM64(SB), NOSPLIT, $0-16
+	MOVD a+8(FP), R16
+	LDP  0(R16), (R0, R1)
+	LDP  16(R16), (R2, R3)
+	MOVD R0, R4
+	MOVD R1, R5
+	MOVD R2, R6
+	MOVD R3, R7
+	MUL_REDUCE
+	MOVD out+0(FP), R16
+	STP  (R12, R13), 0(R16)
+	STP  (R14, R15), 16(R16)
+	RET
@@ -0,0 +1,104 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego && secp256k1_arm64asm
+
+package secp256k1
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestMulARM64(t *testing.T) {
+	elements := limbTestElements(t)
+	for i, a := range elements {
+		for _, b := range []*Element{a, elements[(i+1)%len(elements)], elements[i%10], elements[len(elements)-1-i]} {
+			var got, want Element
+			mulARM64(&got, a, b)
+			want.mulGeneric(a, b)
+			if got != want {
+				t.Fatalf("mulARM64(%x, %x) = %x, want %x", *a, *b, got, want)
+			}
+		}
+
+		var got, want Element
+		squareARM64(&got, a)
+		want.squareGeneric(a)
+		if got != want {
+			t.Fatalf("squareARM64(%x) = %x, want %x", *a, got, want)
+		}
+	}
+
+	// The output may overlap with the inputs.
+	a, b := randomElement(t), randomElement(t)
+	want := new(Element).mulGeneric(a, b)
+	if got := new(Element).Set(a); func() bool { mulARM64(got, got, b); return *got != *want }() {
+		t.Errorf("mulARM64 with out aliasing a = %x, want %x", *got, *want)
+	}
+	want.squareGeneric(a)
+	if got := new(Element).Set(a); func() bool { squareARM64(got, got); return *got != *want }() {
+		t.Errorf("squareARM64 with out aliasing a = %x, want %x", *got, *want)
+	}
+}
+
+// FuzzMulARM64 compares mulARM64 and squareARM64 with the portable
+// fiat-crypto implementations on arbitrary reduced limbs.
+func FuzzMulARM64(f *testing.F) {
+	for _, e := range limbTestElements(f)[:16] {
+		var b [ElementLength]byte
+		for i := range e {
+			binary.LittleEndian.PutUint64(b[8*i:], e[i])
+		}
+		f.Add(b[:], b[:])
+	}
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		var x, y Element
+		for i := 0; i < 4 && 8*i+8 <= len(a); i++ {
+			x[i] = binary.LittleEndian.Uint64(a[8*i:])
+		}
+		for i := 0; i < 4 && 8*i+8 <= len(b); i++ {
+			y[i] = binary.LittleEndian.Uint64(b[8*i:])
+		}
+		if cmpLimbs((*[4]uint64)(&x), &fieldPrime) >= 0 || cmpLimbs((*[4]uint64)(&y), &fieldPrime) >= 0 {
+			return
+		}
+
+		var got, want Element
+		mulARM64(&got, &x, &y)
+		want.mulGeneric(&x, &y)
+		if got != want {
+			t.Errorf("mulARM64(%x, %x) = %x, want %x", x, y, got, want)
+		}
+		squareARM64(&got, &x)
+		want.squareGeneric(&x)
+		if got != want {
+			t.Errorf("squareARM64(%x) = %x, want %x", x, got, want)
+		}
+	})
+}
+
+func BenchmarkMulARM64(b *testing.B) {
+	x, y := randomElement(b), randomElement(b)
+	b.Run("mulGeneric", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			x.mulGeneric(x, y)
+		}
+	})
+	b.Run("mulARM64", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mulARM64(x, x, y)
+		}
+	})
+	b.Run("squareGeneric", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			x.squareGeneric(x)
+		}
+	})
+	b.Run("squareARM64", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			squareARM64(x, x)
+		}
+	})
+}
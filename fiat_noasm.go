@@ -0,0 +1,11 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build (!amd64 && !arm64) || !gc || purego || (arm64 && !secp256k1_arm64asm)
+
+package secp256k1
+
+func mul(e, t1, t2 *Element) { e.mulGeneric(t1, t2) }
+
+func square(e, t *Element) { e.squareGeneric(t) }
@@ -0,0 +1,127 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+// generatorTableSize is the length of the serialized generator table: 64
+// tables of 15 points, each encoded as a 64-byte affine X || Y.
+const generatorTableSize = ElementLength * 2 * 15 * 2 * ElementLength
+
+// DumpGeneratorTable returns the serialized precomputed table of multiples of
+// the generator used by ScalarBaseMult, computing it if necessary.
+//
+// The format is the concatenation of the 64 tables, in order. Table i holds
+// [1]Bᵢ to [15]Bᵢ in order, where Bᵢ = [16ⁱ]G, and each point is encoded as
+// its 32-byte big-endian affine X coordinate followed by its Y coordinate.
+// The output is 61440 bytes long.
+func DumpGeneratorTable() []byte {
+	tables := NewPoint().generatorTable()
+
+	var zs, zinvs []*Element
+	for i := range tables {
+		for _, p := range tables[i] {
+			zs = append(zs, p.Z)
+			zinvs = append(zinvs, new(Element))
+		}
+	}
+	InvertBatch(zinvs, zs)
+
+	out := make([]byte, 0, generatorTableSize)
+	x, y := new(Element), new(Element)
+	for i := range tables {
+		for j, p := range tables[i] {
+			zinv := zinvs[i*15+j]
+			out = append(out, x.Mul(p.X, zinv).Bytes()...)
+			out = append(out, y.Mul(p.Y, zinv).Bytes()...)
+		}
+	}
+	return out
+}
+
+// GeneratorTableDigest returns the SHA-256 digest of the serialized generator
+// table, as returned by DumpGeneratorTable. It is a single value that can be
+// pinned to check that the table matches the specification, for example as
+// part of a reproducible build, and that a table loaded with
+// SetGeneratorTable is the expected one.
+//
+// The digest of the correct table is
+// 32954bcde546b36770f22617308bf7aa37357e073975557cac12489d87dd813a.
+func GeneratorTableDigest() [32]byte {
+	return sha256.Sum256(DumpGeneratorTable())
+}
+
+// SetGeneratorTable loads a generator table serialized by DumpGeneratorTable,
+// so that ScalarBaseMult doesn't have to compute it on first use. It must be
+// called before any operation that uses the table, or it returns an error.
+//
+// Every point is checked to be on the curve, and a few entries are checked
+// against the generator and each other, but the table is otherwise trusted:
+// a table that passes these checks but is incorrect will make ScalarBaseMult
+// return incorrect results. It should only be loaded from a trusted source,
+// such as data embedded in the binary.
+func SetGeneratorTable(data []byte) error {
+	if len(data) != generatorTableSize {
+		return errors.New("invalid secp256k1 generator table length")
+	}
+	tables := new([ElementLength * 2]table)
+	for i := range tables {
+		for j := range tables[i] {
+			off := (i*15 + j) * 2 * ElementLength
+			x, err := new(Element).SetBytes(data[off : off+ElementLength])
+			if err != nil {
+				return err
+			}
+			y, err := new(Element).SetBytes(data[off+ElementLength : off+2*ElementLength])
+			if err != nil {
+				return err
+			}
+			if err := CheckOnCurve(x, y); err != nil {
+				return err
+			}
+			tables[i][j] = &Point{X: x, Y: y, Z: new(Element).One()}
+		}
+	}
+	if err := checkGeneratorTable(tables); err != nil {
+		return err
+	}
+
+	set := false
+	generatorTableOnce.Do(func() {
+		generatorTable = tables
+		set = true
+	})
+	if !set {
+		return errors.New("secp256k1 generator table is already initialized")
+	}
+	return nil
+}
+
+// checkGeneratorTable spot-checks the first, a middle, and the last table: the
+// base of the first one must be G, each base must be [16] times the previous
+// one, and every entry must be the right multiple of its base.
+func checkGeneratorTable(tables *[ElementLength * 2]table) error {
+	if tables[0][0].Equal(NewGenerator()) != 1 {
+		return errors.New("invalid secp256k1 generator table")
+	}
+	t := NewPoint()
+	for _, i := range []int{0, ElementLength, ElementLength*2 - 1} {
+		if i > 0 {
+			// [16]Bᵢ₋₁ = [2]([8]Bᵢ₋₁)
+			if tables[i][0].Equal(t.Double(tables[i-1][7])) != 1 {
+				return errors.New("invalid secp256k1 generator table")
+			}
+		}
+		for j := 1; j < 15; j++ {
+			if tables[i][j].Equal(t.Add(tables[i][j-1], tables[i][0])) != 1 {
+				return errors.New("invalid secp256k1 generator table")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"testing"
+)
+
+// resetGeneratorTable makes the generator table uninitialized for the
+// duration of the test.
+func resetGeneratorTable(t *testing.T) {
+	saved := NewPoint().generatorTable()
+	generatorTable, generatorTableOnce = nil, sync.Once{}
+	t.Cleanup(func() {
+		generatorTable, generatorTableOnce = saved, sync.Once{}
+		generatorTableOnce.Do(func() {})
+	})
+}
+
+func TestGeneratorTableRoundTrip(t *testing.T) {
+	data := DumpGeneratorTable()
+	if len(data) != 61440 {
+		t.Fatalf("table is %d bytes, want 61440", len(data))
+	}
+	if !bytes.Equal(data[:64], NewGenerator().Bytes()[1:]) {
+		t.Errorf("first entry is not the generator")
+	}
+
+	resetGeneratorTable(t)
+	if err := SetGeneratorTable(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(DumpGeneratorTable(), data) {
+		t.Errorf("loaded table does not round-trip")
+	}
+	for _, k := range [][]byte{randomScalar(t), orderMinusOne} {
+		got, err := NewPoint().ScalarBaseMult(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := NewPoint().ScalarMult(NewGenerator(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(want) != 1 {
+			t.Errorf("ScalarBaseMult with loaded table is incorrect")
+		}
+	}
+
+	if err := SetGeneratorTable(data); err == nil {
+		t.Error("SetGeneratorTable succeeded after initialization")
+	}
+}
+
+func TestSetGeneratorTableInvalid(t *testing.T) {
+	data := DumpGeneratorTable()
+	resetGeneratorTable(t)
+
+	offCurve := append([]byte(nil), data...)
+	offCurve[100] ^= 1
+	// Swapping two entries keeps every point on the curve.
+	swapped := append([]byte(nil), data...)
+	copy(swapped[64:128], data[128:192])
+	copy(swapped[128:192], data[64:128])
+	// Negating the last table keeps every relation within the table.
+	negated := append([]byte(nil), data...)
+	for j := 0; j < 15; j++ {
+		off := (63*15+j)*64 + 32
+		y, _ := new(Element).SetBytes(data[off : off+32])
+		copy(negated[off:], y.CondNegate(y, 1).Bytes())
+	}
+
+	for name, d := range map[string][]byte{
+		"short":     data[:len(data)-1],
+		"off curve": offCurve,
+		"swapped":   swapped,
+		"negated":   negated,
+	} {
+		if err := SetGeneratorTable(d); err == nil {
+			t.Errorf("%s: SetGeneratorTable succeeded", name)
+		}
+	}
+	if generatorTable != nil {
+		t.Errorf("failed SetGeneratorTable initialized the table")
+	}
+}
+
+// generatorTableDigest is the SHA-256 of the serialized generator table,
+// computed independently with affine arithmetic from the generator.
+const generatorTableDigest = "32954bcde546b36770f22617308bf7aa37357e073975557cac12489d87dd813a"
+
+func TestGeneratorTableDigest(t *testing.T) {
+	got := GeneratorTableDigest()
+	if hex.EncodeToString(got[:]) != generatorTableDigest {
+		t.Errorf("GeneratorTableDigest() = %x, want %s", got, generatorTableDigest)
+	}
+	if want := sha256.Sum256(DumpGeneratorTable()); got != want {
+		t.Errorf("GeneratorTableDigest() = %x, want SHA-256 of DumpGeneratorTable %x", got, want)
+	}
+}
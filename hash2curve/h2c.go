@@ -0,0 +1,268 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hash2curve implements hashing to secp256k1 as specified in RFC 9380,
+// with the secp256k1_XMD:SHA-256_SSWU_RO_ and secp256k1_XMD:SHA-256_SSWU_NU_
+// suites.
+//
+// secp256k1 has a = 0, so the simplified SWU map is applied to the 3-isogenous
+// curve E': y² = x³ + A'x + B', and the result is mapped back with the isogeny
+// of RFC 9380, Appendix E.1.
+package hash2curve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// HashToCurve hashes msg to a point, using the domain separation tag dst, as
+// specified by the secp256k1_XMD:SHA-256_SSWU_RO_ suite of RFC 9380. The
+// output distribution is indistinguishable from uniformly random points.
+func HashToCurve(msg, dst []byte) (*secp256k1.Point, error) {
+	u, err := hashToField(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	q0, err := mapToCurve(u[0])
+	if err != nil {
+		return nil, err
+	}
+	q1, err := mapToCurve(u[1])
+	if err != nil {
+		return nil, err
+	}
+	// The cofactor of secp256k1 is one, so clear_cofactor is the identity.
+	return q0.Add(q0, q1), nil
+}
+
+// EncodeToCurve hashes msg to a point, using the domain separation tag dst, as
+// specified by the secp256k1_XMD:SHA-256_SSWU_NU_ suite of RFC 9380. It is
+// about twice as fast as HashToCurve, but its output is not uniformly
+// distributed, so it must only be used where the protocol allows it.
+func EncodeToCurve(msg, dst []byte) (*secp256k1.Point, error) {
+	u, err := hashToField(msg, dst, 1)
+	if err != nil {
+		return nil, err
+	}
+	return mapToCurve(u[0])
+}
+
+// hashToField implements hash_to_field from RFC 9380, Section 5.2, with m = 1
+// and L = 48.
+func hashToField(msg, dst []byte, count int) ([]*secp256k1.Element, error) {
+	const L = 48
+	uniform, err := expandMessageXMD(msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+	u := make([]*secp256k1.Element, count)
+	for i := range u {
+		u[i] = reduceWide(uniform[i*L : (i+1)*L])
+	}
+	return u, nil
+}
+
+// reduceWide returns the 48-byte big-endian value b reduced modulo p.
+func reduceWide(b []byte) *secp256k1.Element {
+	// b = hi·2¹⁹² + lo, where hi and lo are 24 bytes long and so lower than p.
+	var buf [secp256k1.ElementLength]byte
+	copy(buf[8:], b[:24])
+	hi, _ := new(secp256k1.Element).SetBytes(buf[:])
+	copy(buf[8:], b[24:])
+	lo, _ := new(secp256k1.Element).SetBytes(buf[:])
+	return hi.Mul(hi, twoTo192).Add(hi, lo)
+}
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380, Section
+// 5.3.1, with SHA-256.
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	const bLen, sLen = sha256.Size, sha256.BlockSize
+	ell := (length + bLen - 1) / bLen
+	if ell > 255 || length > 65535 {
+		return nil, errors.New("hash2curve: requested output is too long")
+	}
+	if len(dst) > 255 {
+		h := sha256.New()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	dstPrime := append(dst[:len(dst):len(dst)], byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sLen))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, ell*bLen)
+	bi := make([]byte, bLen)
+	for i := 1; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime), where
+		// b_0 is XORed with the all-zero b_(i-1) for i = 1.
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// mapToCurve implements map_to_curve_simple_swu from RFC 9380, Section 6.6.2,
+// followed by the 3-isogeny map to secp256k1.
+func mapToCurve(u *secp256k1.Element) (*secp256k1.Point, error) {
+	x, y := sswu(u)
+	return isoMap(x, y)
+}
+
+// sswu maps u to a point (x, y) on E', in constant time, with the straight-line
+// implementation of RFC 9380, Appendix F.2.
+func sswu(u *secp256k1.Element) (x, y *secp256k1.Element) {
+	one := new(secp256k1.Element).One()
+
+	tv1 := new(secp256k1.Element).Square(u)
+	tv1.Mul(sswuZ, tv1)
+	tv2 := new(secp256k1.Element).Square(tv1)
+	tv2.Add(tv2, tv1)
+	tv3 := new(secp256k1.Element).Add(tv2, one)
+	tv3.Mul(isoB, tv3)
+	tv4 := new(secp256k1.Element).Sub(new(secp256k1.Element), tv2)
+	tv4.Select(tv4, sswuZ, tv2.IsZero()^1)
+	tv4.Mul(isoA, tv4)
+	tv2.Square(tv3)
+	tv6 := new(secp256k1.Element).Square(tv4)
+	tv5 := new(secp256k1.Element).Mul(isoA, tv6)
+	tv2.Add(tv2, tv5)
+	tv2.Mul(tv2, tv3)
+	tv6.Mul(tv6, tv4)
+	tv5.Mul(isoB, tv6)
+	tv2.Add(tv2, tv5)
+	x = new(secp256k1.Element).Mul(tv1, tv3)
+	isGx1Square, y1 := sqrtRatio(tv2, tv6)
+	y = new(secp256k1.Element).Mul(tv1, u)
+	y.Mul(y, y1)
+	x.Select(tv3, x, isGx1Square)
+	y.Select(y1, y, isGx1Square)
+	y.CondNegate(y, sgn0(u)^sgn0(y))
+	x.Mul(x, tv4.Invert(tv4))
+	return x, y
+}
+
+// sqrtRatio implements sqrt_ratio for p ≡ 3 mod 4, from RFC 9380, Appendix
+// F.2.1.2. It returns 1 and sqrt(u/v) if u/v is square, and 0 and sqrt(Z·u/v)
+// otherwise.
+func sqrtRatio(u, v *secp256k1.Element) (isQR int, y *secp256k1.Element) {
+	tv1 := new(secp256k1.Element).Square(v)
+	tv2 := new(secp256k1.Element).Mul(u, v)
+	tv1.Mul(tv1, tv2)
+	y1 := new(secp256k1.Element).Exp(tv1, sqrtRatioC1)
+	y1.Mul(y1, tv2)
+	y2 := new(secp256k1.Element).Mul(y1, sqrtRatioC2)
+	tv3 := new(secp256k1.Element).Square(y1)
+	tv3.Mul(tv3, v)
+	isQR = tv3.Equal(u)
+	return isQR, y2.Select(y1, y2, isQR)
+}
+
+// sgn0 returns the parity of e, as specified in RFC 9380, Section 4.1.
+func sgn0(e *secp256k1.Element) int {
+	return e.IsOdd()
+}
+
+// isoMap maps the point (x, y) on E' to secp256k1, with the 3-isogeny from
+// RFC 9380, Appendix E.1.
+func isoMap(x, y *secp256k1.Element) (*secp256k1.Point, error) {
+	xNum := horner(x, isoK[0]...)
+	xDen := horner(x, isoK[1]...)
+	yNum := horner(x, isoK[2]...)
+	yDen := horner(x, isoK[3]...)
+
+	// The denominators only vanish for the few points of E' in the kernel of
+	// the isogeny, which RFC 9380 maps to the identity.
+	if xDen.IsZero()|yDen.IsZero() == 1 {
+		return secp256k1.NewPoint(), nil
+	}
+	xNum.Mul(xNum, xDen.Invert(xDen))
+	yNum.Mul(yNum, yDen.Invert(yDen))
+	yNum.Mul(yNum, y)
+
+	return secp256k1.NewPoint().SetAffine(xNum, yNum)
+}
+
+// horner evaluates the polynomial with the given coefficients, from the
+// constant term up, at x. A nil coefficient stands for one.
+func horner(x *secp256k1.Element, k ...*secp256k1.Element) *secp256k1.Element {
+	r := new(secp256k1.Element)
+	for i := len(k) - 1; i >= 0; i-- {
+		r.Mul(r, x)
+		if k[i] == nil {
+			r.Add(r, new(secp256k1.Element).One())
+		} else {
+			r.Add(r, k[i])
+		}
+	}
+	return r
+}
+
+var (
+	// isoA and isoB are the coefficients of E'.
+	isoA = mustElement("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533")
+	isoB = mustElement("00000000000000000000000000000000000000000000000000000000000006eb")
+	// sswuZ is Z = -11.
+	sswuZ = mustElement("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc24")
+	// sqrtRatioC1 is (p - 3) / 4, and sqrtRatioC2 is sqrt(-Z).
+	sqrtRatioC1, _ = hex.DecodeString("3fffffffffffffffffffffffffffffffffffffffffffffffffffffffbfffff0b")
+	sqrtRatioC2    = mustElement("31fdf302724013e57ad13fb38f842afeec184f00a74789dd286729c8303c4a59")
+
+	twoTo192 = mustElement("0000000000000001000000000000000000000000000000000000000000000000")
+
+	// isoK holds the coefficients of x_num, x_den, y_num, and y_den, from the
+	// constant term up. The leading coefficients of the denominators are one.
+	isoK = [4][]*secp256k1.Element{
+		{
+			mustElement("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7"),
+			mustElement("07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581"),
+			mustElement("534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262"),
+			mustElement("8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c"),
+		},
+		{
+			mustElement("d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b"),
+			mustElement("edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14"),
+			nil,
+		},
+		{
+			mustElement("4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c"),
+			mustElement("c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3"),
+			mustElement("29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931"),
+			mustElement("2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84"),
+		},
+		{
+			mustElement("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b"),
+			mustElement("7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573"),
+			mustElement("6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f"),
+			nil,
+		},
+	}
+)
+
+func mustElement(s string) *secp256k1.Element {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("hash2curve: internal error: invalid constant")
+	}
+	e, err := new(secp256k1.Element).SetBytes(b)
+	if err != nil {
+		panic("hash2curve: internal error: invalid constant")
+	}
+	return e
+}
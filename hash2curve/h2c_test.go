@@ -0,0 +1,114 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash2curve
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors from RFC 9380, Appendix J.8.
+var h2cTests = []struct {
+	suite string
+	f     func(msg, dst []byte) (*secp256k1.Point, error)
+	dst   string
+	msg   string
+	x, y  string
+}{
+	{
+		"RO", HashToCurve, "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_", "",
+		"c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346",
+		"64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067",
+	},
+	{
+		"RO", HashToCurve, "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_", "abc",
+		"3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b",
+		"7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6",
+	},
+	{
+		"NU", EncodeToCurve, "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_NU_", "",
+		"a4792346075feae77ac3b30026f99c1441b4ecf666ded19b7522cf65c4c55c5b",
+		"62c59e2a6aeed1b23be5883e833912b08ba06be7f57c0e9cdc663f31639ff3a7",
+	},
+	{
+		"NU", EncodeToCurve, "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_NU_", "abc",
+		"3f3b5842033fff837d504bb4ce2a372bfeadbdbd84a1d2b678b6e1d7ee426b9d",
+		"902910d1fef15d8ae2006fc84f2a5a7bda0e0407dc913062c3a493c4f5d876a5",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, tt := range h2cTests {
+		p, err := tt.f([]byte(tt.msg), []byte(tt.dst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := append([]byte{4}, decodeHex(t, tt.x+tt.y)...)
+		if got := p.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("%s(%q) = %x, want %x", tt.suite, tt.msg, got, want)
+		}
+	}
+}
+
+func TestExpandMessageXMDLongDST(t *testing.T) {
+	// A DST longer than 255 bytes is replaced by its hash, so both must give
+	// the same output.
+	long := bytes.Repeat([]byte("x"), 256)
+	got, err := expandMessageXMD([]byte("msg"), long, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), long...))
+	want, err := expandMessageXMD([]byte("msg"), h[:], 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("oversize DST was not hashed")
+	}
+
+	if _, err := expandMessageXMD(nil, []byte("dst"), 256*32); err == nil {
+		t.Error("expected error for oversized output")
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_")
+	for i := 0; i < b.N; i++ {
+		HashToCurve([]byte("abc"), dst)
+	}
+}
+
+func TestSSWU(t *testing.T) {
+	// Every u must map to a point on E', taking both branches of the map.
+	for i := 0; i < 64; i++ {
+		u := reduceWide(bytes.Repeat([]byte{byte(i), 0x5a, byte(3 * i)}, 16))
+		x, y := sswu(u)
+		lhs := new(secp256k1.Element).Square(y)
+		rhs := new(secp256k1.Element).Square(x)
+		rhs.Add(rhs, isoA)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, isoB)
+		if lhs.Equal(rhs) != 1 {
+			t.Fatalf("sswu(%x) = (%x, %x) is not on E'", u.Bytes(), x.Bytes(), y.Bytes())
+		}
+		if sgn0(u) != sgn0(y) {
+			t.Errorf("sswu(%x): sign of y does not match u", u.Bytes())
+		}
+	}
+}
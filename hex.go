@@ -0,0 +1,72 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ParseHex decodes a hex-encoded point, in any of the encodings accepted by
+// SetBytes, with an optional 0x prefix.
+func ParseHex(s string) (*Point, error) {
+	b, err := decodeHexString(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewPoint().SetBytes(b)
+}
+
+// Hex returns the hex-encoded compressed encoding of p, without a prefix. It
+// is "00" for the point at infinity.
+func (p *Point) Hex() string {
+	var buf [1 + ElementLength]byte
+	var out [2 * len(buf)]byte
+	return string(appendHex(out[:0], p.bytesCompressed(&buf)))
+}
+
+// ParseElementHex decodes a hex-encoded 32-byte big-endian field element, with
+// an optional 0x prefix. Like SetBytes, it rejects non-canonical values.
+func ParseElementHex(s string) (*Element, error) {
+	b, err := decodeHexString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(Element).SetBytes(b)
+}
+
+// Hex returns the canonical value of e as 64 hex digits, without a prefix.
+func (e *Element) Hex() string {
+	var buf [ElementLength]byte
+	var out [2 * ElementLength]byte
+	return string(appendHex(out[:0], e.bytes(&buf)))
+}
+
+// appendHex appends the lowercase hex encoding of b to dst, without a prefix,
+// and returns the extended slice. It is the encoder shared by Hex, String and
+// MarshalJSON, as decodeHexString is the shared decoder.
+func appendHex(dst, b []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, hex.EncodedLen(len(b)))...)
+	hex.Encode(dst[n:], b)
+	return dst
+}
+
+// decodeHexString decodes s, after removing an optional 0x or 0X prefix. It is
+// the decoder shared by ParseHex, ParseElementHex and UnmarshalJSON.
+func decodeHexString(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	if len(s)%2 != 0 {
+		return nil, errors.New("invalid secp256k1 hex encoding: odd length")
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid secp256k1 hex encoding")
+	}
+	return b, nil
+}
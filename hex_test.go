@@ -0,0 +1,67 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import "testing"
+
+func TestPointHex(t *testing.T) {
+	const gHex = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	if got := NewGenerator().Hex(); got != gHex {
+		t.Errorf("Hex() = %s, want %s", got, gHex)
+	}
+	for _, s := range []string{gHex, "0x" + gHex, "0X" + gHex,
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"} {
+		p, err := ParseHex(s)
+		if err != nil {
+			t.Fatalf("ParseHex(%s): %v", s, err)
+		}
+		if p.IsGenerator() != 1 || p.Hex() != gHex {
+			t.Errorf("ParseHex(%s) = %v, want the generator", s, p)
+		}
+	}
+
+	if p, err := ParseHex(NewPoint().Hex()); err != nil || p.IsInfinity() != 1 {
+		t.Errorf("infinity round trip: got %v, %v", p, err)
+	}
+
+	for _, s := range []string{
+		gHex[1:],        // odd length
+		"0x" + gHex[1:], // odd length with prefix
+		gHex[:64] + "zz",
+		"",
+		"0x",
+		"02" + "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+	} {
+		if _, err := ParseHex(s); err == nil {
+			t.Errorf("ParseHex(%q): expected error", s)
+		}
+	}
+}
+
+func TestElementHex(t *testing.T) {
+	const minusOne = "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e"
+	for _, s := range []string{minusOne, "0x" + minusOne} {
+		e, err := ParseElementHex(s)
+		if err != nil {
+			t.Fatalf("ParseElementHex(%s): %v", s, err)
+		}
+		if got := e.Hex(); got != minusOne {
+			t.Errorf("ParseElementHex(%s).Hex() = %s", s, got)
+		}
+	}
+	if got := new(Element).One().Hex(); got != "0000000000000000000000000000000000000000000000000000000000000001" {
+		t.Errorf("One().Hex() = %s", got)
+	}
+	for _, s := range []string{
+		minusOne[1:],
+		"01",
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", // p
+	} {
+		if _, err := ParseElementHex(s); err == nil {
+			t.Errorf("ParseElementHex(%q): expected error", s)
+		}
+	}
+}
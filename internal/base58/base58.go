@@ -0,0 +1,122 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package base58 implements the Bitcoin base58 and base58check encodings.
+package base58
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var decodeMap = func() (m [256]int8) {
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		m[alphabet[i]] = int8(i)
+	}
+	return m
+}()
+
+// Encode returns the base58 encoding of b. Each leading zero byte is encoded
+// as a leading '1'.
+func Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) ≈ 1.37, so this is always enough room.
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, c := range b[zeros:] {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = alphabet[d]
+	}
+	return string(out)
+}
+
+// Decode decodes the base58 string s.
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) ≈ 0.733, so this is always enough room.
+	bytes := make([]byte, 0, len(s)*733/1000+1)
+	for i := zeros; i < len(s); i++ {
+		d := decodeMap[s[i]]
+		if d < 0 {
+			return nil, errors.New("base58: invalid character")
+		}
+		carry := int(d)
+		for j := range bytes {
+			carry += int(bytes[j]) * 58
+			bytes[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			bytes = append(bytes, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(bytes))
+	for i, b := range bytes {
+		out[len(out)-1-i] = b
+	}
+	return out, nil
+}
+
+// CheckEncode returns the base58check encoding of payload, which is the base58
+// encoding of payload followed by the first four bytes of its double SHA-256.
+func CheckEncode(payload []byte) string {
+	sum := checksum(payload)
+	return Encode(append(payload[:len(payload):len(payload)], sum[:]...))
+}
+
+// CheckDecode decodes the base58check string s and verifies its checksum.
+func CheckDecode(s string) ([]byte, error) {
+	b, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, errors.New("base58: input too short")
+	}
+	payload, sum := b[:len(b)-4], b[len(b)-4:]
+	want := checksum(payload)
+	if subtle.ConstantTimeCompare(sum, want[:]) != 1 {
+		return nil, errors.New("base58: invalid checksum")
+	}
+	return payload, nil
+}
+
+func checksum(b []byte) [4]byte {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	var out [4]byte
+	copy(out[:], h[:])
+	return out
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+var base58Tests = []struct {
+	hex, enc string
+}{
+	{"", ""},
+	{"00", "1"},
+	{"0000", "11"},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for i, tt := range base58Tests {
+		b, _ := hex.DecodeString(tt.hex)
+		if got := Encode(b); got != tt.enc {
+			t.Errorf("%d: Encode(%s) = %q, want %q", i, tt.hex, got, tt.enc)
+		}
+		got, err := Decode(tt.enc)
+		if err != nil {
+			t.Errorf("%d: Decode(%q): %v", i, tt.enc, err)
+			continue
+		}
+		if !bytes.Equal(got, b) {
+			t.Errorf("%d: Decode(%q) = %x, want %s", i, tt.enc, got, tt.hex)
+		}
+	}
+	for _, s := range []string{"0", "O", "I", "l", "abc!"} {
+		if _, err := Decode(s); err == nil {
+			t.Errorf("Decode(%q): expected error", s)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	payload := []byte{0x80, 1, 2, 3}
+	s := CheckEncode(payload)
+	got, err := CheckDecode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Errorf("got %x, want %x", got, payload)
+	}
+
+	b, _ := Decode(s)
+	b[len(b)-1] ^= 1
+	if _, err := CheckDecode(Encode(b)); err == nil {
+		t.Error("expected checksum error")
+	}
+	if _, err := CheckDecode("1"); err == nil {
+		t.Error("expected error for short input")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bech32 implements the encoding of segregated witness addresses, with
+// the bech32 checksum of BIP 173 for version 0 programs, and the bech32m
+// checksum of BIP 350 for later versions.
+package bech32
+
+import "errors"
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of bech32 and bech32m.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// hrpExpand returns the human-readable part as checksummed, the high bits of
+// each character, a zero, and the low bits of each character.
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// encode returns the bech32 or bech32m string for hrp and the 5-bit values
+// of data.
+func encode(hrp string, data []byte, constant uint32) string {
+	values := append(hrpExpand(hrp), data...)
+	mod := polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+	out := make([]byte, 0, len(hrp)+1+len(data)+6)
+	out = append(out, hrp...)
+	out = append(out, '1')
+	for _, v := range data {
+		out = append(out, charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		out = append(out, charset[(mod>>(5*(5-i)))&31])
+	}
+	return string(out)
+}
+
+// convertBits regroups the 8-bit bytes of data into 5-bit values, padding the
+// last one with zero bits.
+func convertBits(data []byte) []byte {
+	var out []byte
+	acc, n := uint32(0), uint(0)
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		n += 8
+		for n >= 5 {
+			n -= 5
+			out = append(out, byte(acc>>n)&31)
+		}
+	}
+	if n > 0 {
+		out = append(out, byte(acc<<(5-n))&31)
+	}
+	return out
+}
+
+// EncodeSegwit returns the address of the witness program of the given
+// version, with the human-readable part hrp, such as "bc" for mainnet or "tb"
+// for testnet. Version 0 programs must be 20 or 32 bytes, and all programs 2
+// to 40 bytes.
+func EncodeSegwit(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 {
+		return "", errors.New("bech32: invalid witness version")
+	}
+	if len(program) < 2 || len(program) > 40 ||
+		version == 0 && len(program) != 20 && len(program) != 32 {
+		return "", errors.New("bech32: invalid witness program length")
+	}
+	constant := uint32(bech32mConst)
+	if version == 0 {
+		constant = bech32Const
+	}
+	data := append([]byte{version}, convertBits(program)...)
+	return encode(hrp, data, constant), nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bech32
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// TestEncodeSegwit uses the valid addresses of BIP 173 and BIP 350, given as
+// address and scriptPubKey, whose first byte is the version (OP_0 or OP_1 to
+// OP_16) and second byte the program length.
+func TestEncodeSegwit(t *testing.T) {
+	tests := []struct {
+		addr, script string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "0014751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		{"BC1SW50QGDZ25J", "6002751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", "5210751e76e8199196d454941c45d1b3a323"},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", "5120000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433"},
+	}
+	for _, tt := range tests {
+		script, err := hex.DecodeString(tt.script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := script[0]
+		if version != 0 {
+			version -= 0x50
+		}
+		hrp := strings.ToLower(tt.addr[:strings.LastIndexByte(tt.addr, '1')])
+		got, err := EncodeSegwit(hrp, version, script[2:])
+		if err != nil {
+			t.Errorf("%s: %v", tt.addr, err)
+			continue
+		}
+		if got != strings.ToLower(tt.addr) {
+			t.Errorf("EncodeSegwit(%q, %d, %x) = %s, want %s", hrp, version, script[2:], got, tt.addr)
+		}
+	}
+
+	for _, tt := range []struct {
+		version byte
+		length  int
+	}{{0, 21}, {0, 16}, {1, 1}, {1, 41}, {17, 32}} {
+		if _, err := EncodeSegwit("bc", tt.version, make([]byte, tt.length)); err == nil {
+			t.Errorf("EncodeSegwit accepted version %d with a %d-byte program", tt.version, tt.length)
+		}
+	}
+}
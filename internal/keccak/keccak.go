@@ -0,0 +1,138 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package keccak implements the legacy Keccak-256 hash used by Ethereum. It
+// differs from SHA3-256, as standardized in FIPS 202, only in the padding.
+package keccak
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of a Keccak-256 checksum in bytes.
+const Size = 32
+
+// rate is the number of bytes absorbed per permutation, 1600 bits minus twice
+// the 256-bit security level.
+const rate = 136
+
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations and lanes are the ρ offsets and π lane order of the combined ρ and
+// π steps, following the lane visited after lane 1.
+var rotations = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+var lanes = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+
+// keccakF1600 applies the Keccak-f[1600] permutation to a.
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	for round := 0; round < 24; round++ {
+		// θ
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+
+		// ρ and π
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := lanes[i]
+			t, a[j] = a[j], bits.RotateLeft64(t, rotations[i])
+		}
+
+		// χ
+		for y := 0; y < 25; y += 5 {
+			copy(c[:], a[y:y+5])
+			for x := 0; x < 5; x++ {
+				a[y+x] = c[x] ^ (^c[(x+1)%5] & c[(x+2)%5])
+			}
+		}
+
+		// ι
+		a[0] ^= roundConstants[round]
+	}
+}
+
+type digest struct {
+	a   [25]uint64
+	buf [rate]byte
+	n   int
+}
+
+// New256 returns a new hash.Hash computing the Keccak-256 checksum.
+func New256() hash.Hash {
+	return &digest{}
+}
+
+// Sum256 returns the Keccak-256 checksum of data.
+func Sum256(data []byte) [Size]byte {
+	var d digest
+	d.Write(data)
+	var out [Size]byte
+	d.checkSum(&out)
+	return out
+}
+
+func (d *digest) Size() int      { return Size }
+func (d *digest) BlockSize() int { return rate }
+
+func (d *digest) Reset() {
+	*d = digest{}
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n == rate {
+			d.absorb()
+		}
+	}
+	return n, nil
+}
+
+func (d *digest) absorb() {
+	for i := 0; i < rate/8; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(d.buf[8*i:])
+	}
+	keccakF1600(&d.a)
+	d.n = 0
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing.
+	d0 := *d
+	var out [Size]byte
+	d0.checkSum(&out)
+	return append(b, out[:]...)
+}
+
+func (d *digest) checkSum(out *[Size]byte) {
+	// The original Keccak pad10*1 padding, with no domain separation bits.
+	for i := d.n; i < rate; i++ {
+		d.buf[i] = 0
+	}
+	d.buf[d.n] ^= 0x01
+	d.buf[rate-1] ^= 0x80
+	d.absorb()
+	for i := 0; i < Size/8; i++ {
+		binary.LittleEndian.PutUint64(out[8*i:], d.a[i])
+	}
+}
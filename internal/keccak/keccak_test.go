@@ -0,0 +1,46 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package keccak
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSum256(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+		// Exactly one block, which needs a second block for the padding.
+		{strings.Repeat("a", rate), ""},
+	}
+	for _, tt := range tests {
+		got := Sum256([]byte(tt.in))
+		if tt.want != "" && hex.EncodeToString(got[:]) != tt.want {
+			t.Errorf("Sum256(%q) = %x, want %s", tt.in, got, tt.want)
+		}
+
+		// Writing in pieces must give the same result.
+		h := New256()
+		for i := 0; i < len(tt.in); i += 7 {
+			end := i + 7
+			if end > len(tt.in) {
+				end = len(tt.in)
+			}
+			h.Write([]byte(tt.in[i:end]))
+		}
+		if sum := h.Sum(nil); !bytes.Equal(sum, got[:]) {
+			t.Errorf("New256 of %q = %x, want %x", tt.in, sum, got)
+		}
+		if sum := h.Sum(nil); !bytes.Equal(sum, got[:]) {
+			t.Errorf("second Sum of %q = %x, want %x", tt.in, sum, got)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ripemd160
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "9c1185a5c5e9fc54612808977ee8f548b2258d31"},
+		{"a", "0bdc9d2d256b3ee9daae347be6f4dc835a467ffe"},
+		{"abc", "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+		{"message digest", "5d0689ef49d2fae572b881b123a85ffa21595f36"},
+		// The lengths around the padding boundaries.
+		{strings.Repeat("a", 55), "0d8a8c9063a48576a7c97e9f95253a6e53ff6765"},
+		{strings.Repeat("a", 56), "e72334b46c83cc70bef979e15453706c95b888be"},
+		{strings.Repeat("a", 64), "9dfb7d374ad924f3f88de96291c33e9abed53e32"},
+		{strings.Repeat("a", 1000000), "52783243c1697bdbe16d37f97f68f08325dc1528"},
+	}
+	for _, tt := range tests {
+		got := Sum([]byte(tt.in))
+		if hex.EncodeToString(got[:]) != tt.want {
+			t.Errorf("Sum(%.16q, %d bytes) = %x, want %s", tt.in, len(tt.in), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package wifcodec implements the layout of Bitcoin's Wallet Import Format,
+// shared by the wif package and secp256k1.NewPrivateKeyFromWIF: the
+// base58check encoding of a network version byte, a 32-byte private key, and
+// an optional 0x01 suffix marking a compressed public key.
+//
+// The private key is not range checked, which is left to the callers.
+package wifcodec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wdvxdr1123/secp256k1/internal/base58"
+)
+
+// Network version bytes.
+const (
+	MainnetVersion = 0x80
+	TestnetVersion = 0xef
+)
+
+// KeyLength is the length of the encoded private key.
+const KeyLength = 32
+
+// compressedSuffix follows the key for keys of compressed public keys.
+const compressedSuffix = 0x01
+
+// Encode returns the WIF encoding of key, which must be KeyLength bytes long.
+func Encode(key []byte, compressed, mainnet bool) string {
+	if len(key) != KeyLength {
+		panic("wifcodec: invalid key length")
+	}
+	payload := make([]byte, 0, 1+KeyLength+1)
+	if mainnet {
+		payload = append(payload, MainnetVersion)
+	} else {
+		payload = append(payload, TestnetVersion)
+	}
+	payload = append(payload, key...)
+	if compressed {
+		payload = append(payload, compressedSuffix)
+	}
+	return base58.CheckEncode(payload)
+}
+
+// Decode decodes s, checking its checksum, and returns the KeyLength-byte key
+// along with whether it is marked as compressed and whether it uses the
+// mainnet version byte. The errors have no prefix, for the callers to add
+// their own.
+func Decode(s string) (key []byte, compressed, mainnet bool, err error) {
+	payload, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("invalid encoding: %w", err)
+	}
+
+	switch {
+	case len(payload) == 1+KeyLength:
+	case len(payload) == 1+KeyLength+1 && payload[len(payload)-1] == compressedSuffix:
+		compressed = true
+	default:
+		return nil, false, false, errors.New("invalid length")
+	}
+	switch payload[0] {
+	case MainnetVersion:
+		mainnet = true
+	case TestnetVersion:
+	default:
+		return nil, false, false, fmt.Errorf("invalid version byte %#x", payload[0])
+	}
+	return payload[1 : 1+KeyLength], compressed, mainnet, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wifcodec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1/internal/base58"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// The layout doesn't range check the key, so any 32 bytes round trip.
+	keys := [][]byte{make([]byte, KeyLength), bytes.Repeat([]byte{0xff}, KeyLength)}
+	for _, key := range keys {
+		for _, compressed := range []bool{false, true} {
+			for _, mainnet := range []bool{false, true} {
+				s := Encode(key, compressed, mainnet)
+				got, c, m, err := Decode(s)
+				if err != nil {
+					t.Fatalf("Decode(%s): %v", s, err)
+				}
+				if !bytes.Equal(got, key) || c != compressed || m != mainnet {
+					t.Errorf("Decode(%s) = %x, %v, %v, want %x, %v, %v", s, got, c, m, key, compressed, mainnet)
+				}
+			}
+		}
+	}
+
+	if got := Encode(bytes.Repeat([]byte{0x0c}, KeyLength), false, true); got[0] != '5' {
+		t.Errorf("uncompressed mainnet key %s doesn't start with 5", got)
+	}
+
+	key := make([]byte, KeyLength)
+	for _, s := range []string{
+		"",
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvy0J",
+		base58.CheckEncode(append([]byte{MainnetVersion}, key[1:]...)),
+		base58.CheckEncode(append(append([]byte{MainnetVersion}, key...), 0x02)),
+		base58.CheckEncode(append([]byte{0x00}, key...)),
+	} {
+		if _, _, _, err := Decode(s); err == nil {
+			t.Errorf("Decode(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonInfinity is the JSON encoding of the point at infinity.
+const jsonInfinity = "infinity"
+
+// MarshalJSON implements json.Marshaler. It encodes p as a JSON string holding
+// the hex-encoded compressed encoding of p, or "infinity" for the point at
+// infinity.
+func (p *Point) MarshalJSON() ([]byte, error) {
+	if p.Z.IsZero() == 1 {
+		return []byte(`"` + jsonInfinity + `"`), nil
+	}
+	var buf [1 + ElementLength]byte
+	out := make([]byte, 0, 2+2*len(buf))
+	out = append(out, '"')
+	out = appendHex(out, p.bytesCompressed(&buf))
+	return append(out, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON string holding
+// "infinity" or a hex-encoded point in any of the encodings accepted by
+// SetBytes, with an optional 0x prefix like ParseHex, and rejects malformed hex
+// and points not on the curve. As is
+// conventional, a JSON null leaves p unchanged.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.New("invalid secp256k1 point JSON encoding: not a string")
+	}
+	q := NewPoint()
+	if s != jsonInfinity {
+		b, err := decodeHexString(s)
+		if err != nil {
+			return err
+		}
+		if _, err := q.SetBytes(b); err != nil {
+			return err
+		}
+	}
+	// p may be the zero value, as allocated by encoding/json, so replace its
+	// coordinates rather than setting them.
+	*p = *q
+	return nil
+}
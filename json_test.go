@@ -0,0 +1,85 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPointJSON(t *testing.T) {
+	// G has an even Y coordinate, so -G has an odd one.
+	negG := NewPoint().Negate(NewGenerator())
+	for _, tt := range []struct {
+		name string
+		p    *Point
+		want string
+	}{
+		{"infinity", NewPoint(), `"infinity"`},
+		{"generator", NewGenerator(), `"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"`},
+		{"odd Y", negG, `"0379be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"`},
+	} {
+		got, err := json.Marshal(tt.p)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+		// Decode into the zero value, as encoding/json does for pointer fields.
+		var v struct{ P *Point }
+		if err := json.Unmarshal([]byte(`{"P":`+string(got)+`}`), &v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if v.P.Equal(tt.p) != 1 {
+			t.Errorf("%s: round trip got %x, want %x", tt.name, v.P.Bytes(), tt.p.Bytes())
+		}
+	}
+
+	// Uncompressed encodings and the SEC 1 infinity encoding are accepted too.
+	g := NewPoint()
+	if err := json.Unmarshal([]byte(`"`+hex.EncodeToString(NewGenerator().Bytes())+`"`), g); err != nil || g.Equal(NewGenerator()) != 1 {
+		t.Errorf("uncompressed: got %x, %v", g.Bytes(), err)
+	}
+	if err := json.Unmarshal([]byte(`"00"`), g); err != nil || g.IsInfinity() != 1 {
+		t.Errorf("00: got %x, %v", g.Bytes(), err)
+	}
+	// As with ParseHex, a 0x prefix is accepted.
+	if err := json.Unmarshal([]byte(`"0x`+NewGenerator().Hex()+`"`), g); err != nil || g.Equal(NewGenerator()) != 1 {
+		t.Errorf("0x prefix: got %x, %v", g.Bytes(), err)
+	}
+
+	// null is a no-op.
+	g = NewGenerator()
+	if err := json.Unmarshal([]byte(`null`), g); err != nil || g.Equal(NewGenerator()) != 1 {
+		t.Errorf("null: got %x, %v", g.Bytes(), err)
+	}
+
+	for _, in := range []string{
+		`"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179"`,    // odd length
+		`"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179z"`,   // not hex
+		`"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798ff"`, // too long
+		`"05` + strings.Repeat("00", 32) + `"`,                                   // bad type byte
+		`"02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"`,   // x = p
+		`"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798` +
+			`483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b9"`, // off curve
+		`""`,
+		`"0x"`,
+		`"0x0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179"`, // odd length with prefix
+		`"Infinity"`,
+		`42`,
+		`{}`,
+	} {
+		p := NewGenerator()
+		if err := json.Unmarshal([]byte(in), p); err == nil {
+			t.Errorf("%s: expected error", in)
+		}
+		if p.Equal(NewGenerator()) != 1 {
+			t.Errorf("%s: receiver modified on error", in)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import "errors"
+
+// Marshal returns the uncompressed encoding of p, as specified in SEC 1,
+// Version 2.0, Section 2.3.3. For points other than the point at infinity, the
+// output is the same as that of crypto/elliptic.Marshal for the curve returned
+// by the elliptic subpackage.
+//
+// The point at infinity is encoded as the single byte 0x00, which
+// crypto/elliptic can't represent.
+func Marshal(p *Point) []byte {
+	return p.Bytes()
+}
+
+// Unmarshal decodes a point in the uncompressed form, as produced by Marshal or
+// crypto/elliptic.Marshal. Like crypto/elliptic.Unmarshal, it rejects the
+// compressed and hybrid forms, the encoding of the point at infinity, and
+// points not on the curve. Use Point.SetBytes to accept any SEC 1 encoding.
+func Unmarshal(data []byte) (*Point, error) {
+	if len(data) != 1+2*ElementLength || data[0] != 4 {
+		return nil, errors.New("invalid secp256k1 uncompressed point encoding")
+	}
+	return NewPoint().SetBytes(data)
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1_test
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+	s256 "github.com/wdvxdr1123/secp256k1/elliptic"
+)
+
+func TestMarshalInterop(t *testing.T) {
+	curve := s256.S256()
+	for i := 0; i < 8; i++ {
+		k := make([]byte, 32)
+		if _, err := rand.Read(k); err != nil {
+			t.Fatal(err)
+		}
+		x, y := curve.ScalarBaseMult(k)
+
+		// crypto/elliptic to secp256k1.
+		std := elliptic.Marshal(curve, x, y)
+		p, err := secp256k1.Unmarshal(std)
+		if err != nil {
+			t.Fatalf("Unmarshal(%x): %v", std, err)
+		}
+		want, err := secp256k1.NewPoint().ScalarBaseMult(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Equal(want) != 1 {
+			t.Errorf("Unmarshal(%x) = %v, want %v", std, p, want)
+		}
+
+		// secp256k1 to crypto/elliptic.
+		out := secp256k1.Marshal(want)
+		if !bytes.Equal(out, std) {
+			t.Errorf("Marshal = %x, want %x", out, std)
+		}
+		gotX, gotY := elliptic.Unmarshal(curve, out)
+		if gotX == nil || gotX.Cmp(x) != 0 || gotY.Cmp(y) != 0 {
+			t.Errorf("elliptic.Unmarshal(%x) = (%x, %x), want (%x, %x)", out, gotX, gotY, x, y)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	curve := s256.S256()
+	params := curve.Params()
+	g := elliptic.Marshal(curve, params.Gx, params.Gy)
+	offCurve := append(g[:64:64], g[64]^1)
+	zero := append([]byte{4}, make([]byte, 64)...)
+
+	for name, data := range map[string][]byte{
+		"empty":      nil,
+		"infinity":   {0},
+		"compressed": elliptic.MarshalCompressed(curve, params.Gx, params.Gy),
+		"hybrid":     append([]byte{6}, g[1:]...),
+		"truncated":  g[:64],
+		"long":       append(g[:65:65], 0),
+		"off curve":  offCurve,
+		"zero":       zero,
+	} {
+		if _, err := secp256k1.Unmarshal(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		// The standard library agrees.
+		if x, _ := elliptic.Unmarshal(curve, data); x != nil {
+			t.Errorf("%s: elliptic.Unmarshal succeeded", name)
+		}
+	}
+
+	if got := secp256k1.Marshal(secp256k1.NewPoint()); !bytes.Equal(got, []byte{0}) {
+		t.Errorf("Marshal(infinity) = %x, want 00", got)
+	}
+}
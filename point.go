@@ -6,7 +6,11 @@ package secp256k1
 
 import (
 	"crypto/subtle"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
+	"math/bits"
 	"sync"
 )
 
@@ -17,12 +21,9 @@ var b, _ = new(Element).SetBytes([]byte{
 	0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x7,
 })
 
-var b3, _ = new(Element).SetBytes([]byte{
-	0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-	0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-	0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-	0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x15,
-})
+// b3 is 3b, the multiple of b used by the complete addition formulas. It is
+// applied with MulInt, which is cheaper than a full field multiplication.
+const b3 = 3 * 7
 
 var g, _ = NewPoint().SetBytes([]byte{0x4, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb, 0xac, 0x55, 0xa0, 0x62, 0x95, 0xce, 0x87, 0xb, 0x7, 0x2, 0x9b, 0xfc, 0xdb, 0x2d, 0xce, 0x28, 0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17, 0x98, 0x48, 0x3a, 0xda, 0x77, 0x26, 0xa3, 0xc4, 0x65, 0x5d, 0xa4, 0xfb, 0xfc, 0xe, 0x11, 0x8, 0xa8, 0xfd, 0x17, 0xb4, 0x48, 0xa6, 0x85, 0x54, 0x19, 0x9c, 0x47, 0xd0, 0x8f, 0xfb, 0x10, 0xd4, 0xb8})
 
@@ -54,6 +55,18 @@ func NewGenerator() *Point {
 	}).Set(g)
 }
 
+// SetGenerator sets p to the canonical generator, and returns p. Unlike
+// NewGenerator, it doesn't allocate.
+func (p *Point) SetGenerator() *Point {
+	return p.Set(g)
+}
+
+// IsGenerator returns 1 if p is the canonical generator, and zero otherwise.
+// It runs in constant time.
+func (p *Point) IsGenerator() int {
+	return p.Equal(g)
+}
+
 // Set sets p = q and returns p.
 func (p *Point) Set(q *Point) *Point {
 	p.X.Set(q.X)
@@ -62,18 +75,18 @@ func (p *Point) Set(q *Point) *Point {
 	return p
 }
 
-// SetBytes sets p to the compressed, uncompressed, or infinity value encoded in
-// b, as specified in SEC 1, Version 2.0, Section 2.3.4. If the point is not on
-// the curve, it returns nil and an error, and the receiver is unchanged.
-// Otherwise, it returns p.
+// SetBytes sets p to the compressed, uncompressed, hybrid, or infinity value
+// encoded in b, as specified in SEC 1, Version 2.0, Section 2.3.4. If the point
+// is not on the curve, it returns nil and an error, and the receiver is
+// unchanged. Otherwise, it returns p.
 func (p *Point) SetBytes(b []byte) (_ *Point, e error) {
 	switch {
 	// Point at infinity.
 	case len(b) == 1 && b[0] == 0:
 		return p.Set(NewPoint()), nil
 
-	// Uncompressed form.
-	case len(b) == 1+2*ElementLength && b[0] == 4:
+	// Uncompressed or hybrid form.
+	case len(b) == 1+2*ElementLength && (b[0] == 4 || b[0] == 6 || b[0] == 7):
 		x, err := new(Element).SetBytes(b[1 : 1+ElementLength])
 		if err != nil {
 			return nil, err
@@ -82,7 +95,10 @@ func (p *Point) SetBytes(b []byte) (_ *Point, e error) {
 		if err != nil {
 			return nil, err
 		}
-		if err := checkOnCurve(x, y); err != nil {
+		if err := CheckOnCurve(x, y); err != nil {
+			return nil, err
+		}
+		if err := checkHybridParity(b); err != nil {
 			return nil, err
 		}
 		p.X.Set(x)
@@ -100,15 +116,12 @@ func (p *Point) SetBytes(b []byte) (_ *Point, e error) {
 		// Y² = X³ + b
 		y := polynomial(new(Element), x)
 		if !sqrt(y, y) {
-			return nil, errors.New("invalid secp256k1 compressed point encoding")
+			return nil, errCompressedNotOnCurve
 		}
 
 		// Select the positive or negative root, as indicated by the least
 		// significant bit, based on the encoding type byte.
-		otherRoot := new(Element)
-		otherRoot.Sub(otherRoot, y)
-		cond := y.Bytes()[ElementLength-1]&1 ^ b[0]&1
-		y.Select(otherRoot, y, int(cond))
+		y.CondNegate(y, y.IsOdd()^int(b[0]&1))
 
 		p.X.Set(x)
 		p.Y.Set(y)
@@ -116,10 +129,31 @@ func (p *Point) SetBytes(b []byte) (_ *Point, e error) {
 		return p, nil
 
 	default:
-		return nil, errors.New("invalid secp256k1 point encoding")
+		return nil, pointEncodingError(b)
+	}
+}
+
+// pointEncodingError returns the error for an encoding with an unknown length
+// or type byte.
+func pointEncodingError(b []byte) error {
+	switch len(b) {
+	case 1, 1 + ElementLength, 1 + 2*ElementLength:
+		return errors.New("invalid secp256k1 point encoding")
+	default:
+		return errPointLength
 	}
 }
 
+// checkHybridParity checks that the type byte of a 65-byte hybrid encoding,
+// 6 or 7, matches the least significant bit of the encoded Y coordinate. The
+// uncompressed encoding, with type byte 4, carries no parity.
+func checkHybridParity(b []byte) error {
+	if b[0] != 4 && b[0]&1 != b[len(b)-1]&1 {
+		return errors.New("invalid secp256k1 hybrid point encoding: Y parity mismatch")
+	}
+	return nil
+}
+
 // polynomial sets y2 to X³ + b, and returns y2.
 func polynomial(y2, x *Element) *Element {
 	y2.Square(x)         // y2 := x  * x
@@ -127,19 +161,97 @@ func polynomial(y2, x *Element) *Element {
 	return y2.Add(y2, b) // y2 := y2 + b
 }
 
-func checkOnCurve(x, y *Element) error {
+// CheckOnCurve returns an error if the affine coordinates (x, y) are not those
+// of a point on the curve, that is if y² ≠ x³ + 7. The error matches
+// ErrNotOnCurve with errors.Is.
+//
+// It is meant for deserializers that decode the coordinates themselves, and
+// must check them before building a point. The point at infinity has no
+// affine coordinates, and is never accepted.
+func CheckOnCurve(x, y *Element) error {
 	// Y² = X³ + b
 	rhs := polynomial(new(Element), x)
 	lhs := new(Element).Square(y)
 	if rhs.Equal(lhs) != 1 {
-		return errors.New("secp256k1 point not on curve")
+		return errPointNotOnCurve
+	}
+	return nil
+}
+
+// SetBytesBatch sets points[i] to the point encoded in encodings[i], in any of
+// the forms accepted by SetBytes, allocating points[i] if it is nil.
+//
+// If any encoding is invalid, SetBytesBatch returns an error that includes
+// the index of the first invalid encoding, and none of the points are
+// modified. It also returns an error if the slices have different lengths.
+func SetBytesBatch(points []*Point, encodings [][]byte) error {
+	if len(points) != len(encodings) {
+		return errors.New("mismatched number of points and encodings")
+	}
+	decoded := make([]Point, len(encodings))
+	elements := make([]Element, 3*len(encodings))
+	for i, b := range encodings {
+		p := &decoded[i]
+		p.X, p.Y, p.Z = &elements[3*i], &elements[3*i+1], &elements[3*i+2]
+		if _, err := p.SetBytes(b); err != nil {
+			return fmt.Errorf("invalid secp256k1 point encoding at index %d: %w", i, err)
+		}
+	}
+	for i := range points {
+		if points[i] == nil {
+			points[i] = NewPoint()
+		}
+		points[i].Set(&decoded[i])
 	}
 	return nil
 }
 
+// ValidatePublicKeyBytes checks that b is a compressed, uncompressed, or hybrid
+// SEC 1 encoding of a point on the curve other than the point at infinity, as
+// specified in SEC 1, Version 2.0, Section 2.3.4.
+//
+// It performs the same checks as SetBytes, but in a single pass that doesn't
+// allocate and doesn't round-trip field elements through their byte encoding,
+// so it is considerably faster when the decoded point itself is not needed.
+func ValidatePublicKeyBytes(b []byte) error {
+	var x, y, lhs, rhs Element
+	switch {
+	case len(b) == 1+2*ElementLength && (b[0] == 4 || b[0] == 6 || b[0] == 7):
+		if x.setCanonicalBytes(b[1:1+ElementLength]) != 1 ||
+			y.setCanonicalBytes(b[1+ElementLength:]) != 1 {
+			return errElementNonCanonical
+		}
+		// Y² = X³ + b
+		polynomial(&rhs, &x)
+		lhs.Square(&y)
+		if lhs.equal(&rhs) != 1 {
+			return errPointNotOnCurve
+		}
+		return checkHybridParity(b)
+
+	case len(b) == 1+ElementLength && (b[0] == 2 || b[0] == 3):
+		if x.setCanonicalBytes(b[1:]) != 1 {
+			return errElementNonCanonical
+		}
+		// X³ + b must be a square for a Y to exist. Both roots are valid,
+		// so the sign byte needs no further checks.
+		polynomial(&rhs, &x)
+		sqrtCandidate(&y, &rhs)
+		lhs.Square(&y)
+		if lhs.equal(&rhs) != 1 {
+			return errCompressedNotOnCurve
+		}
+		return nil
+
+	default:
+		return pointEncodingError(b)
+	}
+}
+
 // Bytes returns the uncompressed or infinity encoding of p, as specified in
 // SEC 1, Version 2.0, Section 2.3.3. Note that the encoding of the point at
-// infinity is shorter than all other encodings.
+// infinity is the single byte 0x00, shorter than all other encodings, so
+// callers that need a fixed 65-byte output should use BytesFixed.
 func (p *Point) Bytes() []byte {
 	// This function is outlined to make the allocations inline in the caller
 	// rather than happen on the heap.
@@ -162,6 +274,54 @@ func (p *Point) bytes(out *[1 + 2*ElementLength]byte) []byte {
 	return buf
 }
 
+// AppendBytes appends the uncompressed or infinity encoding of p, as returned
+// by Bytes, to dst and returns the extended slice. It doesn't allocate if dst
+// has enough spare capacity, which is 65 bytes for any point.
+func (p *Point) AppendBytes(dst []byte) []byte {
+	var out [1 + 2*ElementLength]byte
+	return append(dst, p.bytes(&out)...)
+}
+
+// BytesFixed returns the 65-byte uncompressed encoding of p, like Bytes, or an
+// error if p is the point at infinity, so it never returns a short slice.
+func (p *Point) BytesFixed() ([]byte, error) {
+	// This function is outlined to make the allocations inline in the caller
+	// rather than happen on the heap.
+	var out [1 + 2*ElementLength]byte
+	if p.Z.IsZero() == 1 {
+		return nil, errInfinity
+	}
+	return p.bytes(&out), nil
+}
+
+// BytesRaw64 returns the 64-byte X ‖ Y encoding of p, which is the
+// uncompressed encoding without the 0x04 type byte, as used for example by
+// Ethereum. It returns an error if p is the point at infinity, which has no
+// such encoding.
+func (p *Point) BytesRaw64() ([]byte, error) {
+	// This function is outlined to make the allocations inline in the caller
+	// rather than happen on the heap.
+	var out [1 + 2*ElementLength]byte
+	if p.Z.IsZero() == 1 {
+		return nil, errInfinity
+	}
+	return p.bytes(&out)[1:], nil
+}
+
+// SetBytesRaw64 sets p to the point encoded in the 64-byte X ‖ Y form returned
+// by BytesRaw64, and returns p. If b is not 64 bytes, the coordinates are not
+// canonical, or they are not those of a point on the curve, SetBytesRaw64
+// returns nil and an error, and p is unchanged.
+func (p *Point) SetBytesRaw64(b []byte) (*Point, error) {
+	if len(b) != 2*ElementLength {
+		return nil, errPointLength
+	}
+	var buf [1 + 2*ElementLength]byte
+	buf[0] = 4
+	copy(buf[1:], b)
+	return p.SetBytes(buf[:])
+}
+
 // BytesX returns the encoding of the X-coordinate of p, as specified in SEC 1,
 // Version 2.0, Section 2.3.5, or an error if p is the point at infinity.
 func (p *Point) BytesX() ([]byte, error) {
@@ -172,7 +332,7 @@ func (p *Point) BytesX() ([]byte, error) {
 }
 func (p *Point) bytesX(out *[ElementLength]byte) ([]byte, error) {
 	if p.Z.IsZero() == 1 {
-		return nil, errors.New("P256K1 point is the point at infinity")
+		return nil, errInfinity
 	}
 	zinv := new(Element).Invert(p.Z)
 	x := new(Element).Mul(p.X, zinv)
@@ -201,11 +361,111 @@ func (p *Point) bytesCompressed(out *[1 + ElementLength]byte) []byte {
 	// Encode the sign of the Y coordinate (indicated by the least significant
 	// bit) as the encoding type (2 or 3).
 	buf := append(out[:0], 2)
-	buf[0] |= y.Bytes()[ElementLength-1] & 1
+	buf[0] |= byte(y.IsOdd())
 	buf = append(buf, x.Bytes()...)
 	return buf
 }
 
+// AppendBytesCompressed appends the compressed or infinity encoding of p, as
+// returned by BytesCompressed, to dst and returns the extended slice. It
+// doesn't allocate if dst has enough spare capacity, which is 33 bytes for any
+// point.
+func (p *Point) AppendBytesCompressed(dst []byte) []byte {
+	var out [1 + ElementLength]byte
+	return append(dst, p.bytesCompressed(&out)...)
+}
+
+// String returns the curve name followed by the hex-encoded compressed
+// encoding of p, or "secp256k1: ∞" for the point at infinity. It is meant for
+// debugging, and is not constant time.
+//
+// The zero value of Point is not a valid point, and is printed as
+// "secp256k1: invalid point".
+func (p *Point) String() string {
+	if p == nil || p.X == nil || p.Y == nil || p.Z == nil {
+		return "secp256k1: invalid point"
+	}
+	if p.Z.IsZero() == 1 {
+		return "secp256k1: ∞"
+	}
+	const prefix = "secp256k1: "
+	var buf [1 + ElementLength]byte
+	var out [len(prefix) + 2*len(buf)]byte
+	return string(appendHex(append(out[:0], prefix...), p.bytesCompressed(&buf)))
+}
+
+// Affine returns the affine coordinates of p, computed with a single field
+// inversion, or an error if p is the point at infinity.
+func (p *Point) Affine() (x, y *Element, err error) {
+	if p.Z.IsZero() == 1 {
+		return nil, nil, errInfinity
+	}
+	zinv := new(Element).Invert(p.Z)
+	x = new(Element).Mul(p.X, zinv)
+	y = new(Element).Mul(p.Y, zinv)
+	return x, y, nil
+}
+
+// SetAffine sets p to the point with affine coordinates (x, y), and returns p.
+// If (x, y) is not on the curve, SetAffine returns nil and an error, and p is
+// unchanged.
+func (p *Point) SetAffine(x, y *Element) (*Point, error) {
+	q := &Point{
+		X: new(Element).Set(x),
+		Y: new(Element).Set(y),
+		Z: new(Element).One(),
+	}
+	if q.IsOnCurve() != 1 {
+		return nil, errPointNotOnCurve
+	}
+	return p.Set(q), nil
+}
+
+// BytesXOnly returns the 32-byte x-only encoding of p, as specified in BIP340,
+// or an error if p is the point at infinity.
+//
+// The encoding omits the Y coordinate, so p and -p have the same encoding.
+// SetBytesXOnly decodes it to the one of the two with an even Y coordinate.
+func (p *Point) BytesXOnly() ([]byte, error) {
+	var out [ElementLength]byte
+	return p.bytesX(&out)
+}
+
+// SetBytesXOnly sets p to the point with X coordinate b and an even Y
+// coordinate, as specified by the lift_x function of BIP340, and returns p.
+// If b is not 32 bytes, it is not a canonical field element, or it is not the
+// X coordinate of a point, SetBytesXOnly returns nil and an error, and p is
+// unchanged.
+func (p *Point) SetBytesXOnly(b []byte) (*Point, error) {
+	if len(b) != ElementLength {
+		return nil, errXOnlyLength
+	}
+	x, err := new(Element).SetBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	// sqrt returns the root with an even canonical encoding, which is the
+	// one BIP340 selects.
+	y := polynomial(new(Element), x)
+	if !sqrt(y, y) {
+		return nil, errXOnlyNotOnCurve
+	}
+
+	p.X.Set(x)
+	p.Y.Set(y)
+	p.Z.One()
+	return p, nil
+}
+
+// LiftX returns the point with X coordinate x and an even Y coordinate, as
+// specified by the lift_x function of BIP340. Unlike the decompression done by
+// SetBytes, the parity of Y is not part of the input, and x must be exactly
+// 32 bytes. LiftX returns an error if x is not lower than p, or if x³ + 7 is
+// not a square modulo p.
+func LiftX(x []byte) (*Point, error) {
+	return NewPoint().SetBytesXOnly(x)
+}
+
 // Add sets q = p1 + p2, and returns q. The points may overlap.
 func (p *Point) Add(p1, p2 *Point) *Point {
 	// Complete addition formula for a = 0 from "Complete addition formulas for
@@ -229,21 +489,19 @@ func (p *Point) Add(p1, p2 *Point) *Point {
 	x3.Mul(x3, y3)                     // X3 := X3 * Y3
 	y3.Add(t0, t2)                     // Y3 := t0 + t2
 	y3.Sub(x3, y3)                     // Y3 := X3 - Y3
-	x3.Add(t0, t0)                     // X3 := t0 + t0
+	x3.Double(t0)                      // X3 := t0 + t0
 	t0.Add(x3, t0)                     // t0 := X3 + t0
-	t2.Mul(b3, t2)                     // t2 := b3 * t2
+	t2.MulInt(t2, b3)                  // t2 := b3 * t2
 	z3 := new(Element).Add(t1, t2)     // Z3 := t1 * t2
 	t1.Sub(t1, t2)                     // t1 := t1 - t2
-	y3.Mul(b3, y3)                     // Y3 := b3 * Y3
+	y3.MulInt(y3, b3)                  // Y3 := b3 * Y3
 	x3.Mul(t4, y3)                     // X3 := t4 * Y3
 	t2.Mul(t3, t1)                     // t2 := t3 * t1
 	x3.Sub(t2, x3)                     // x3 := t2 - X3
 	y3.Mul(y3, t0)                     // Y3 := Y3 * t0
-	t1.Mul(t1, z3)                     // t1 := t1 * Z3
-	y3.Add(t1, y3)                     // Y3 := t1 + Y3
+	y3.MulAdd(t1, z3, y3)              // Y3 := t1 * Z3 + Y3
 	t0.Mul(t0, t3)                     // t0 := t0 * t3
-	z3.Mul(z3, t4)                     // Z3 := Z3 * t4
-	z3.Add(z3, t0)                     // Z3 := Z3 + t0
+	z3.MulAdd(z3, t4, t0)              // Z3 := Z3 * t4 + t0
 
 	p.X.Set(x3)
 	p.Y.Set(y3)
@@ -276,21 +534,19 @@ func (q *Point) Sub(p1, p2 *Point) *Point {
 	x3.Mul(x3, y3)                     // X3 := X3 * Y3
 	y3.Add(t0, t2)                     // Y3 := t0 + t2
 	y3.Sub(x3, y3)                     // Y3 := X3 - Y3
-	x3.Add(t0, t0)                     // X3 := t0 + t0
+	x3.Double(t0)                      // X3 := t0 + t0
 	t0.Add(x3, t0)                     // t0 := X3 + t0
-	t2.Mul(b3, t2)                     // t2 := b3 * t2
+	t2.MulInt(t2, b3)                  // t2 := b3 * t2
 	z3 := new(Element).Add(t1, t2)     // Z3 := t1 * t2
 	t1.Sub(t1, t2)                     // t1 := t1 - t2
-	y3.Mul(b3, y3)                     // Y3 := b3 * Y3
+	y3.MulInt(y3, b3)                  // Y3 := b3 * Y3
 	x3.Mul(t4, y3)                     // X3 := t4 * Y3
 	t2.Mul(t3, t1)                     // t2 := t3 * t1
 	x3.Sub(t2, x3)                     // x3 := t2 - X3
 	y3.Mul(y3, t0)                     // Y3 := Y3 * t0
-	t1.Mul(t1, z3)                     // t1 := t1 * Z3
-	y3.Add(t1, y3)                     // Y3 := t1 + Y3
+	y3.MulAdd(t1, z3, y3)              // Y3 := t1 * Z3 + Y3
 	t0.Mul(t0, t3)                     // t0 := t0 * t3
-	z3.Mul(z3, t4)                     // Z3 := Z3 * t4
-	z3.Add(z3, t0)                     // Z3 := Z3 + t0
+	z3.MulAdd(z3, t4, t0)              // Z3 := Z3 * t4 + t0
 
 	q.X.Set(x3)
 	q.Y.Set(y3)
@@ -304,31 +560,173 @@ func (q *Point) Double(p *Point) *Point {
 	// prime order elliptic curves" (https://eprint.iacr.org/2015/1060), §A.3.
 
 	t0 := new(Element).Square(p.Y)   // t0 := Y^2
-	z3 := new(Element).Add(t0, t0)   // Z3 := t0 + t0
-	z3.Add(z3, z3)                   // Z3 := Z3 + Z3
-	z3.Add(z3, z3)                   // Z3 := Z3 + Z3
+	z3 := new(Element).Double(t0)    // Z3 := t0 + t0
+	z3.Double(z3)                    // Z3 := Z3 + Z3
+	z3.Double(z3)                    // Z3 := Z3 + Z3
 	t1 := new(Element).Mul(p.Y, p.Z) // t1 := Y  * Z
 	t2 := new(Element).Square(p.Z)   // t2 := Z^2
-	t2.Mul(b3, t2)                   // t2 := b3 * t2
+	t2.MulInt(t2, b3)                // t2 := b3 * t2
 	x3 := new(Element).Mul(t2, z3)   // X3 := t2 * Z3
 	y3 := new(Element).Add(t0, t2)   // Y3 := t0 + t2
 	z3.Mul(t1, z3)                   // Z3 := t1 * Z3
-	t1.Add(t2, t2)                   // t1 := t2 + t2
+	t1.Double(t2)                    // t1 := t2 + t2
 	t2.Add(t1, t2)                   // t2 := t1 + t2
 	t0.Sub(t0, t2)                   // t0 := t0 - t2
 	y3.Mul(t0, y3)                   // Y3 := t0 * Y3
 	y3.Add(x3, y3)                   // Y3 := X3 + Y3
 	t1.Mul(p.X, p.Y)                 // t1 := X  * Y
 	x3.Mul(t0, t1)                   // X3 := t0 * t1
-	x3.Add(x3, x3)                   // X3 := X3 + X3
+	x3.Double(x3)                    // X3 := X3 + X3
 
-	p.X.Set(x3)
-	p.Y.Set(y3)
-	p.Z.Set(z3)
+	q.X.Set(x3)
+	q.Y.Set(y3)
+	q.Z.Set(z3)
+	return q
+}
+
+// jacobianMinDoublings is the number of consecutive doublings from which
+// doubleN switches to Jacobian coordinates. On amd64, four Jacobian doublings
+// are already a little faster than four calls to Double, and make ScalarMult,
+// which doubles four times per window, about 8% faster.
+const jacobianMinDoublings = 4
+
+// doubleN sets p = [2ⁿ]q, and returns p. The points may overlap.
+//
+// The doublings are performed in Jacobian coordinates, where x = X/Z² and
+// y = Y/Z³, with the dbl-2009-l formula for a = 0, which costs 2M + 5S against
+// 5M + 3S for Double. Squarings cost about as much as multiplications in this
+// field, though, so each Jacobian doubling only saves about a tenth, and the
+// conversion in and out doesn't pay off below jacobianMinDoublings, where
+// doubleN falls back to Double. The formula is not complete, but the only
+// exceptional input is a point with y = 0, which doesn't exist on secp256k1 as
+// its order is odd, and the point at infinity is preserved.
+func (p *Point) doubleN(q *Point, n int) *Point {
+	if n < jacobianMinDoublings {
+		p.Set(q)
+		for i := 0; i < n; i++ {
+			p.Double(p)
+		}
+		return p
+	}
+
+	// (X : Y : Z) in projective coordinates is (X·Z : Y·Z² : Z) in Jacobian.
+	x := new(Element).Mul(q.X, q.Z)
+	y := new(Element).Square(q.Z)
+	y.Mul(q.Y, y)
+	z := new(Element).Set(q.Z)
+
+	xx, yy, yyyy, d, e := new(Element), new(Element), new(Element), new(Element), new(Element)
+	for i := 0; i < n; i++ {
+		xx.Square(x)         // XX := X²
+		yy.Square(y)         // YY := Y²
+		yyyy.Square(yy)      // YYYY := YY²
+		d.Add(x, yy)         // D := X + YY
+		d.Square(d)          // D := D²
+		d.Sub(d, xx)         // D := D - XX
+		d.Sub(d, yyyy)       // D := D - YYYY
+		d.Double(d)          // D := 2·D
+		e.Double(xx)         // E := 2·XX
+		e.Add(e, xx)         // E := E + XX
+		z.Mul(y, z)          // Z3 := Y·Z
+		z.Double(z)          // Z3 := 2·Z3
+		x.Square(e)          // X3 := E²
+		x.Sub(x, d)          // X3 := X3 - D
+		x.Sub(x, d)          // X3 := X3 - D
+		y.Sub(d, x)          // Y3 := D - X3
+		y.Mul(e, y)          // Y3 := E·Y3
+		yyyy.MulInt(yyyy, 8) // YYYY := 8·YYYY
+		y.Sub(y, yyyy)       // Y3 := Y3 - YYYY
+	}
+
+	// (X : Y : Z) in Jacobian coordinates is (X·Z : Y : Z³) in projective.
+	p.X.Mul(x, z)
+	p.Y.Set(y)
+	p.Z.Square(z)
+	p.Z.Mul(p.Z, z)
+
+	// The point at infinity comes out as (0 : 0 : 0), so restore (0 : 1 : 0).
+	p.Y.Select(new(Element).One(), p.Y, p.Z.IsZero())
+	return p
+}
+
+// IsInfinity returns 1 if p is the point at infinity, and zero otherwise.
+func (p *Point) IsInfinity() int {
+	return p.Z.equal(new(Element))
+}
+
+// IsOnCurve returns 1 if p is a valid point on the curve, including the point
+// at infinity, and zero otherwise. It is useful to validate points whose
+// coordinates were set directly.
+//
+// IsOnCurve checks the projective equation Y²·Z = X³ + b·Z³, so it doesn't
+// need an inversion, and runs in constant time.
+func (p *Point) IsOnCurve() int {
+	lhs := new(Element).Square(p.Y) // Y²
+	lhs.Mul(lhs, p.Z)               // Y²·Z
+	rhs := new(Element).Square(p.X) // X²
+	rhs.Mul(rhs, p.X)               // X³
+	z3 := new(Element).Square(p.Z)  // Z²
+	z3.Mul(z3, p.Z)                 // Z³
+	z3.MulInt(z3, 7)                // b·Z³
+	rhs.Add(rhs, z3)                // X³ + b·Z³
+
+	// (0:0:0) satisfies the equation but is not a point.
+	zero := new(Element)
+	degenerate := p.Y.equal(zero) & p.Z.equal(zero)
+	return lhs.equal(rhs) & (degenerate ^ 1)
+}
+
+// IsInSubgroup returns 1 if p is a valid point in the prime-order subgroup,
+// including the point at infinity, and zero otherwise.
+//
+// secp256k1 has cofactor one, so the group of points on the curve has prime
+// order n and every point on the curve is in the subgroup: the check is the
+// same as IsOnCurve. It exists for code written for curves with a cofactor.
+// Unlike IsOnCurve, it also returns zero rather than panicking for the zero
+// value of Point.
+func (p *Point) IsInSubgroup() int {
+	if p.X == nil || p.Y == nil || p.Z == nil {
+		return 0
+	}
+	return p.IsOnCurve()
+}
+
+// Equal returns 1 if p and q represent the same point, and zero otherwise.
+// All representations of the point at infinity are equal to each other.
+//
+// Equal compares the projective coordinates by cross-multiplication, without
+// inverting Z, and runs in constant time.
+func (p *Point) Equal(q *Point) int {
+	zero := new(Element)
+	pInf := p.Z.equal(zero)
+	qInf := q.Z.equal(zero)
+
+	// X1/Z1 == X2/Z2 ⇔ X1·Z2 == X2·Z1, and likewise for Y.
+	lhs := new(Element).Mul(p.X, q.Z)
+	rhs := new(Element).Mul(q.X, p.Z)
+	eqX := lhs.equal(rhs)
+	lhs.Mul(p.Y, q.Z)
+	rhs.Mul(q.Y, p.Z)
+	eqY := lhs.equal(rhs)
+
+	bothInf := pInf & qInf
+	noneInf := (pInf | qInf) ^ 1
+	return bothInf | noneInf&eqX&eqY
+}
+
+// Negate sets p = -q, and returns p. The points may overlap.
+func (p *Point) Negate(q *Point) *Point {
+	// -(X:Y:Z) = (X:-Y:Z), which also maps the point at infinity (0:1:0) to
+	// the equivalent (0:-1:0).
+	p.X.Set(q.X)
+	p.Y.Sub(new(Element), q.Y)
+	p.Z.Set(q.Z)
 	return p
 }
 
-// Select sets q to p1 if cond == 1, and to p2 if cond == 0.
+// Select sets q to p1 if cond == 1, and to p2 if cond == 0. The behavior is
+// undefined for other values of cond; see CMov for a variant that accepts any
+// value.
 func (p *Point) Select(p1, p2 *Point, cond int) *Point {
 	p.X.Select(p1.X, p2.X, cond)
 	p.Y.Select(p1.Y, p2.Y, cond)
@@ -336,6 +734,15 @@ func (p *Point) Select(p1, p2 *Point, cond int) *Point {
 	return p
 }
 
+// CMov sets p to q if cond is not zero, leaves p unchanged if cond is zero,
+// and returns p. Unlike Select, any nonzero value of cond is treated as 1. It
+// runs in constant time with respect to cond and the points.
+func (p *Point) CMov(q *Point, cond int) *Point {
+	c := uint64(cond)
+	c = (c | -c) >> 63
+	return p.Select(q, p, int(c))
+}
+
 // A table holds the first 15 multiples of a point at offset -1, so [1]P
 // is at table[0], [15]P is at table[14], and [0]P is implicitly the identity
 // point.
@@ -355,6 +762,11 @@ func (table *table) Select(p *Point, n uint8) {
 }
 
 // ScalarMult sets p = scalar * q, and returns p.
+//
+// scalar is a big-endian integer of any length, and it is used as is, without
+// being reduced modulo the order of the group: a 48-byte scalar computes the
+// full 384-bit multiple. Use ScalarMultReduce to work with scalars modulo the
+// order instead.
 func (p *Point) ScalarMult(q *Point, scalar []byte) (*Point, error) {
 	// Compute a table for the base point q. The explicit NewPoint
 	// calls get inlined, letting the allocations live on the stack.
@@ -368,6 +780,45 @@ func (p *Point) ScalarMult(q *Point, scalar []byte) (*Point, error) {
 		table[i+1].Add(table[i], q)
 	}
 
+	return p.scalarMultTable(&table, scalar), nil
+}
+
+// ScalarMultReduce sets p = scalar * q, and returns p. Unlike ScalarMult,
+// scalar is first reduced modulo the order of the group, so it can be any
+// length: shorter scalars are left-padded with zeroes, and an empty scalar is
+// zero, yielding the point at infinity.
+//
+// ScalarMultReduce runs in constant time with respect to the value of scalar,
+// for a given length.
+func (p *Point) ScalarMultReduce(q *Point, scalar []byte) *Point {
+	s := new(Scalar).SetBytesReduce(scalar)
+	if _, err := p.ScalarMult(q, s.Bytes()); err != nil {
+		panic("secp256k1: internal error: ScalarMult rejected a reduced scalar")
+	}
+	return p
+}
+
+// CombinedMult sets p = s1 * G + s2 * q, where G is the canonical generator,
+// and returns p. s1 must be 32 bytes long, and the G term uses the precomputed
+// generator table.
+//
+// CombinedMult runs in constant time with respect to the scalars. For public
+// scalars, such as in signature verification, ScalarDoubleBaseMult computes
+// the same value faster.
+func (p *Point) CombinedMult(q *Point, s1, s2 []byte) (*Point, error) {
+	r, err := NewPoint().ScalarMult(q, s2)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ScalarBaseMult(s1); err != nil {
+		return nil, err
+	}
+	return p.Add(p, r), nil
+}
+
+// scalarMultTable sets p = scalar * q, where table holds the multiples of q,
+// and returns p.
+func (p *Point) scalarMultTable(table *table, scalar []byte) *Point {
 	// Instead of doing the classic double-and-add chain, we do it with a
 	// four-bit window: we double four times, and then add [0-15]P.
 	t := NewPoint()
@@ -376,27 +827,126 @@ func (p *Point) ScalarMult(q *Point, scalar []byte) (*Point, error) {
 		// No need to double on the first iteration, as p is the identity at
 		// this point, and [N]∞ = ∞.
 		if i != 0 {
-			p.Double(p)
-			p.Double(p)
-			p.Double(p)
-			p.Double(p)
+			p.doubleN(p, 4)
 		}
 
 		windowValue := byte >> 4
 		table.Select(t, windowValue)
 		p.Add(p, t)
 
-		p.Double(p)
-		p.Double(p)
-		p.Double(p)
-		p.Double(p)
+		p.doubleN(p, 4)
 
 		windowValue = byte & 0b1111
 		table.Select(t, windowValue)
 		p.Add(p, t)
 	}
 
-	return p, nil
+	return p
+}
+
+// ScalarMultWindow sets p = scalar * q, and returns p, like ScalarMult, but
+// with a signed window of w bits, which must be between 3 and 6. scalar must be
+// 32 bytes long.
+//
+// The table holds the 2^(w-1) odd multiples of q, and the scalar is recoded
+// into ⌈257/w⌉ odd digits, so each window costs w doublings and one addition.
+// Larger windows trade a larger table, which is also scanned in full for every
+// digit, for fewer additions. On amd64, w = 4 and w = 5 are the fastest, on par
+// with ScalarMult, while w = 6 spends more on the table than it saves.
+//
+// ScalarMultWindow runs in constant time with respect to the value of scalar.
+func (p *Point) ScalarMultWindow(q *Point, scalar []byte, w int) (*Point, error) {
+	if w < 3 || w > 6 {
+		return nil, errors.New("invalid scalar multiplication window size")
+	}
+	if len(scalar) != ElementLength {
+		return nil, errors.New("invalid scalar length")
+	}
+
+	// The recoding requires an odd scalar, so an even scalar k is replaced
+	// with k + 1, which can't overflow, and q is subtracted at the end.
+	var k [ElementLength]byte
+	even := 1 - int(scalar[ElementLength-1]&1)
+	carry := even
+	for i := ElementLength - 1; i >= 0; i-- {
+		v := int(scalar[i]) + carry
+		k[i] = byte(v)
+		carry = v >> 8
+	}
+
+	// With the odd multiples [1]q, [3]q, ..., [2^w - 1]q, an odd k can be
+	// written as the sum of dᵢ·2^(w·i) with every dᵢ odd and in
+	// [-(2^w - 1), 2^w - 1] (Joye and Tunstall, "Exponent Recoding and Regular
+	// Exponentiation Algorithms"). The digit dᵢ is the (w+1)-bit window of k at
+	// offset w·i, with its lowest bit forced to one, minus 2^w. The most
+	// significant digit is positive and doesn't need the offset.
+	table := oddMultiples(q, uint(w+1))
+	digits := (8*ElementLength + w) / w
+
+	t, neg := NewPoint(), NewPoint()
+	r := NewPoint()
+	selectOdd(table, t, scalarWindow(k[:], w*(digits-1), w+1)|1)
+	r.Set(t)
+	for i := digits - 2; i >= 0; i-- {
+		r.doubleN(r, w)
+		d := scalarWindow(k[:], w*i, w+1) | 1 - 1<<w
+		sign := int(uint(d) >> (bits.UintSize - 1))
+		abs := (d ^ -sign) + sign
+		selectOdd(table, t, abs)
+		t.Select(neg.Negate(t), t, sign)
+		r.Add(r, t)
+	}
+
+	r.Select(t.Sub(r, q), r, even)
+	return p.Set(r), nil
+}
+
+// ScalarMultLadder sets p = scalar * q, and returns p, like ScalarMult, but
+// uses a Montgomery ladder instead of a window table.
+//
+// The ladder keeps R0 = [k']q and R1 = R0 + q, where k' is the prefix of the
+// scalar processed so far, and performs exactly one addition and one doubling
+// per bit, with the bit only selecting which of the two points is doubled
+// through a pair of constant-time swaps. There are no table lookups, whose
+// timing would depend on the CPU's handling of the scanned memory.
+//
+// The ladder runs on full projective (X : Y : Z) coordinates with the complete
+// formulas of Add and Double, rather than on (X : Z) only, since x-only ladder
+// formulas for short Weierstrass curves have exceptional cases, including the
+// point at infinity the ladder starts from. Y therefore doesn't need to be
+// recovered at the end. The complete formulas are what make it slower: on
+// amd64 it takes about 155µs against 90µs for ScalarMult, so it is meant for
+// callers that prefer avoiding table lookups to speed.
+//
+// scalar is a big-endian integer of any length, used as is like in ScalarMult,
+// and ScalarMultLadder runs in constant time for a given length.
+func (p *Point) ScalarMultLadder(q *Point, scalar []byte) (*Point, error) {
+	r0, r1 := NewPoint(), NewPoint().Set(q)
+	t := NewPoint()
+	for _, byte := range scalar {
+		for i := 7; i >= 0; i-- {
+			bit := int(byte>>i) & 1
+			// Swap so that R0 holds the point to double, and R1 the other one.
+			t.Select(r1, r0, bit)
+			r1.Select(r0, r1, bit)
+			r0.Set(t)
+			r1.Add(r0, r1)
+			r0.Double(r0)
+			t.Select(r1, r0, bit)
+			r1.Select(r0, r1, bit)
+			r0.Set(t)
+		}
+	}
+	return p.Set(r0), nil
+}
+
+// selectOdd sets p to [d]q, where table holds the odd multiples of q and d is
+// odd and positive. It works in constant time by scanning every entry.
+func selectOdd(table []*Point, p *Point, d int) {
+	idx := int32(d >> 1)
+	for i := range table {
+		p.Select(table[i], p, subtle.ConstantTimeEq(int32(i), idx))
+	}
 }
 
 var generatorTable *[ElementLength * 2]table
@@ -414,10 +964,7 @@ func (p *Point) generatorTable() *[ElementLength * 2]table {
 			for j := 1; j < 15; j++ {
 				generatorTable[i][j] = NewPoint().Add(generatorTable[i][j-1], base)
 			}
-			base.Double(base)
-			base.Double(base)
-			base.Double(base)
-			base.Double(base)
+			base.doubleN(base, 4)
 		}
 	})
 	return generatorTable
@@ -455,105 +1002,173 @@ func (p *Point) ScalarBaseMult(scalar []byte) (*Point, error) {
 	return p, nil
 }
 
-// sqrt sets e to a square root of X. If X is not a square, sqrt returns
-// false and e is unchanged. e and X can overlap.
-func sqrt(e, x *Element) (isSquare bool) {
-	candidate := new(Element)
-	sqrtCandidate(candidate, x)
-	square := new(Element).Square(candidate)
-	if square.Equal(x) != 1 {
-		return false
+// ScalarBaseMultCompressed returns the compressed encoding of scalar * B, where
+// B is the canonical generator, such as the public key of the private key
+// scalar. It returns an error if the result is the point at infinity, for
+// example if scalar is zero.
+func ScalarBaseMultCompressed(scalar []byte) ([]byte, error) {
+	p, err := NewPoint().ScalarBaseMult(scalar)
+	if err != nil {
+		return nil, err
+	}
+	return p.compressedNonInfinity()
+}
+
+// ScalarMultCompressed sets p = scalar * q, like ScalarMult, and returns the
+// compressed encoding of p. It returns an error if p is the point at
+// infinity, for example if scalar is zero, but p is still set in that case.
+func (p *Point) ScalarMultCompressed(q *Point, scalar []byte) ([]byte, error) {
+	if _, err := p.ScalarMult(q, scalar); err != nil {
+		return nil, err
 	}
-	e.Set(candidate)
-	return true
+	return p.compressedNonInfinity()
 }
 
-// sqrtCandidate sets Z to a square root candidate for X. Z and X must not overlap.
+func (p *Point) compressedNonInfinity() ([]byte, error) {
+	if p.Z.IsZero() == 1 {
+		return nil, errInfinity
+	}
+	return p.BytesCompressed(), nil
+}
+
+// ScalarBaseMultReduce sets p = scalar * B, where B is the canonical
+// generator, and returns p. Unlike ScalarBaseMult, scalar is a big-endian
+// integer of any length: shorter scalars are left-padded with zeroes, and
+// longer ones are reduced modulo the order of the group. An empty scalar is
+// zero, and yields the point at infinity.
+//
+// ScalarBaseMultReduce runs in constant time with respect to the value of
+// scalar, for a given length.
+func (p *Point) ScalarBaseMultReduce(scalar []byte) *Point {
+	s := new(Scalar).SetBytesReduce(scalar)
+	if _, err := p.ScalarBaseMult(s.Bytes()); err != nil {
+		panic("secp256k1: internal error: ScalarBaseMult rejected a reduced scalar")
+	}
+	return p
+}
+
+// ScalarBaseMultBlinded sets p = scalar * B, where B is the canonical
+// generator, and returns p.
+//
+// Unlike ScalarBaseMult, the scalar is never fed directly to the table lookups.
+// As a countermeasure against differential power analysis, it is split into
+// two shares k1 and k2 = scalar - k1 mod n, where k1 is drawn uniformly from
+// rand for every call, and the result is computed as [k1]G + [k2]G. Each
+// individual multiplication then only observes a value independent of the
+// secret scalar.
+func (p *Point) ScalarBaseMultBlinded(scalar []byte, rand io.Reader) (*Point, error) {
+	if len(scalar) != ElementLength {
+		return nil, errors.New("invalid scalar length")
+	}
+
+	var k1 [ElementLength]byte
+	for {
+		if _, err := io.ReadFull(rand, k1[:]); err != nil {
+			return nil, err
+		}
+		// Rejection sampling keeps k1 uniform in [0, n). The loop runs more
+		// than once with probability less than 2⁻¹²⁷.
+		if lessThanOrder(k1[:]) == 1 {
+			break
+		}
+	}
+
+	var k2 [ElementLength]byte
+	subModOrder(&k2, scalar, k1[:])
+
+	q, err := NewPoint().ScalarBaseMult(k1[:])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ScalarBaseMult(k2[:]); err != nil {
+		return nil, err
+	}
+	return p.Add(p, q), nil
+}
+
+// lessThanOrder returns 1 if the 32-byte big-endian value a is lower than n,
+// and zero otherwise, in constant time.
+func lessThanOrder(a []byte) int {
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		limb := binary.BigEndian.Uint64(a[ElementLength-8*(i+1):])
+		_, borrow = bits.Sub64(limb, order[i], borrow)
+	}
+	return int(borrow)
+}
+
+// subModOrder sets out = a - b mod n, where a and b are 32-byte big-endian
+// values and b < n. If a >= n the result is only congruent to a - b, which is
+// sufficient for scalar multiplication by a point of order n.
+func subModOrder(out *[ElementLength]byte, a, b []byte) {
+	var d [4]uint64
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		x := binary.BigEndian.Uint64(a[ElementLength-8*(i+1):])
+		y := binary.BigEndian.Uint64(b[ElementLength-8*(i+1):])
+		d[i], borrow = bits.Sub64(x, y, borrow)
+	}
+	// If a < b, add n back in constant time.
+	mask := -borrow
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		d[i], carry = bits.Add64(d[i], order[i]&mask, carry)
+	}
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint64(out[ElementLength-8*(i+1):], d[i])
+	}
+}
+
+// sqrt sets e to the square root of X with an even canonical encoding, like
+// Element.Sqrt. If X is not a square, sqrt returns false and e is unchanged.
+// e and X can overlap.
+func sqrt(e, x *Element) (isSquare bool) {
+	return e.Sqrt(x) == 1
+}
+
+// sqrtCandidate sets Z to a square root candidate for X. Z and X can overlap.
 func sqrtCandidate(z, x *Element) {
 	// Since p = 3 mod 4, exponentiation by (p + 1) / 4 yields a square root candidate.
-	//
-	// The sequence of 13 multiplications and 253 squarings is derived from the
-	// following addition chain generated with github.com/mmcloughlin/addchain v0.4.0.
-	//
-	//	_10      = 2*1
-	//	_11      = 1 + _10
-	//	_1100    = _11 << 2
-	//	_1111    = _11 + _1100
-	//	_11110   = 2*_1111
-	//	_11111   = 1 + _11110
-	//	_1111100 = _11111 << 2
-	//	_1111111 = _11 + _1111100
-	//	x11      = _1111111 << 4 + _1111
-	//	x22      = x11 << 11 + x11
-	//	x27      = x22 << 5 + _11111
-	//	x54      = x27 << 27 + x27
-	//	x108     = x54 << 54 + x54
-	//	x216     = x108 << 108 + x108
-	//	x223     = x216 << 7 + _1111111
-	//	return     ((x223 << 23 + x22) << 6 + _11) << 2
-	//
-	var t0 = new(Element)
-	var t1 = new(Element)
-	var t2 = new(Element)
-	var t3 = new(Element)
-
-	z.Square(x)
-	z.Mul(x, z)
-	t0.Square(z)
-	for s := 1; s < 2; s++ {
-		t0.Square(t0)
-	}
-	t0.Mul(z, t0)
-	t1.Square(t0)
-	t2.Mul(x, t1)
-	t1.Square(t2)
-	for s := 1; s < 2; s++ {
-		t1.Square(t1)
-	}
-	t1.Mul(z, t1)
-	t3.Square(t1)
-	for s := 1; s < 4; s++ {
-		t3.Square(t3)
-	}
-	t0.Mul(t0, t3)
-	t3.Square(t0)
-	for s := 1; s < 11; s++ {
-		t3.Square(t3)
-	}
-	t0.Mul(t0, t3)
-	t3.Square(t0)
-	for s := 1; s < 5; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	t3.Square(t2)
-	for s := 1; s < 27; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	t3.Square(t2)
-	for s := 1; s < 54; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	t3.Square(t2)
-	for s := 1; s < 108; s++ {
-		t3.Square(t3)
-	}
-	t2.Mul(t2, t3)
-	for s := 0; s < 7; s++ {
-		t2.Square(t2)
-	}
-	t1.Mul(t1, t2)
-	for s := 0; s < 23; s++ {
-		t1.Square(t1)
-	}
-	t0.Mul(t0, t1)
-	for s := 0; s < 6; s++ {
-		t0.Square(t0)
-	}
-	z.Mul(z, t0)
-	for s := 0; s < 2; s++ {
-		z.Square(z)
-	}
+	z.expChain(x, sqrtChain)
+}
+
+// sqrtChain computes x^((p + 1) / 4) with 13 multiplications and 253
+// squarings. It is derived from the following addition chain generated with
+// github.com/mmcloughlin/addchain v0.4.0.
+//
+//	_10      = 2*1
+//	_11      = 1 + _10
+//	_1100    = _11 << 2
+//	_1111    = _11 + _1100
+//	_11110   = 2*_1111
+//	_11111   = 1 + _11110
+//	_1111100 = _11111 << 2
+//	_1111111 = _11 + _1111100
+//	x11      = _1111111 << 4 + _1111
+//	x22      = x11 << 11 + x11
+//	x27      = x22 << 5 + _11111
+//	x54      = x27 << 27 + x27
+//	x108     = x54 << 54 + x54
+//	x216     = x108 << 108 + x108
+//	x223     = x216 << 7 + _1111111
+//	return     ((x223 << 23 + x22) << 6 + _11) << 2
+var sqrtChain = []expStep{
+	{1, 0, 1, noMul},   // 1: _10
+	{2, 1, 0, 0},       // 2: _11
+	{3, 2, 2, noMul},   // 3: _1100
+	{4, 3, 0, 2},       // 4: _1111
+	{5, 4, 1, noMul},   // 5: _11110
+	{6, 5, 0, 0},       // 6: _11111
+	{7, 6, 2, noMul},   // 7: _1111100
+	{8, 7, 0, 2},       // 8: _1111111
+	{9, 8, 4, 4},       // 9: x11
+	{10, 9, 11, 9},     // 10: x22
+	{11, 10, 5, 6},     // 11: x27
+	{12, 11, 27, 11},   // 12: x54
+	{13, 12, 54, 12},   // 13: x108
+	{14, 13, 108, 13},  // 14: x216
+	{15, 14, 7, 8},     // 15: x223
+	{16, 15, 23, 10},   // 16: x223 << 23 + x22
+	{17, 16, 6, 2},     // 17: ... << 6 + _11
+	{18, 17, 2, noMul}, // 18: ... << 2
 }
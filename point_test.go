@@ -0,0 +1,1371 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// orderMinusOne is n - 1, the largest valid scalar.
+var orderMinusOne = decodeHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140")
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func randomScalar(t testing.TB) []byte {
+	k := make([]byte, ElementLength)
+	if _, err := rand.Read(k); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestScalarBaseMultBlinded(t *testing.T) {
+	scalars := [][]byte{
+		make([]byte, ElementLength),
+		orderMinusOne,
+		bytes.Repeat([]byte{0xff}, ElementLength),
+	}
+	for i := 0; i < 16; i++ {
+		scalars = append(scalars, randomScalar(t))
+	}
+	for i, k := range scalars {
+		want, err := NewPoint().ScalarBaseMult(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewPoint().ScalarBaseMultBlinded(k, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("%d: bad output for k=%x: got %x, want %x", i, k, got.Bytes(), want.Bytes())
+		}
+	}
+
+	if _, err := NewPoint().ScalarBaseMultBlinded(make([]byte, 31), rand.Reader); err == nil {
+		t.Error("expected error for short scalar")
+	}
+}
+
+func TestScalarBaseMultReduce(t *testing.T) {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	k31 := randomScalar(t)[:31]
+	k33 := make([]byte, 33)
+	new(big.Int).Add(n, big.NewInt(5)).FillBytes(k33)
+	k64 := make([]byte, 64)
+	new(big.Int).Add(new(big.Int).Lsh(n, 200), new(big.Int).SetBytes(k31)).FillBytes(k64)
+
+	for _, tt := range []struct {
+		name   string
+		scalar []byte
+		want   []byte
+	}{
+		{"empty", nil, make([]byte, ElementLength)},
+		{"1 byte", []byte{5}, append(make([]byte, 31), 5)},
+		{"31 bytes", k31, append([]byte{0}, k31...)},
+		{"32 bytes", orderMinusOne, orderMinusOne},
+		{"33 bytes", k33, append(make([]byte, 31), 5)},
+		{"64 bytes", k64, append([]byte{0}, k31...)},
+		{"order", append([]byte{0}, n.Bytes()...), make([]byte, ElementLength)},
+	} {
+		want, err := NewPoint().ScalarBaseMult(tt.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := NewPoint().ScalarBaseMultReduce(tt.scalar)
+		if got.Equal(want) != 1 {
+			t.Errorf("%s: got %x, want %x", tt.name, got.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func TestScalarMult(t *testing.T) {
+	g := NewGenerator()
+	for i := 0; i < 8; i++ {
+		k := randomScalar(t)
+		want, err := NewPoint().ScalarBaseMult(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewPoint().ScalarMult(g, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("%d: bad output for k=%x: got %x, want %x", i, k, got.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func TestScalarMultCompressed(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []*Point{NewGenerator(), q} {
+		for i := 0; i < 4; i++ {
+			k := randomScalar(t)
+			want, err := NewPoint().ScalarMult(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := NewPoint()
+			enc, err := p.ScalarMultCompressed(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Equal(want) != 1 {
+				t.Errorf("ScalarMultCompressed didn't set p to %v", want)
+			}
+			if base.Equal(NewGenerator()) == 1 {
+				encBase, err := ScalarBaseMultCompressed(k)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(encBase, enc) {
+					t.Errorf("ScalarBaseMultCompressed(%x) = %x, want %x", k, encBase, enc)
+				}
+			}
+			got, err := NewPoint().SetBytes(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(enc) != 33 || got.Equal(want) != 1 {
+				t.Errorf("%x decodes to %v, want %v", enc, got, want)
+			}
+		}
+	}
+
+	zero := make([]byte, ElementLength)
+	if _, err := ScalarBaseMultCompressed(zero); !errors.Is(err, ErrInfinityNotAllowed) {
+		t.Errorf("ScalarBaseMultCompressed(0): got error %v", err)
+	}
+	if _, err := NewPoint().ScalarMultCompressed(q, zero); !errors.Is(err, ErrInfinityNotAllowed) {
+		t.Errorf("ScalarMultCompressed(0): got error %v", err)
+	}
+	if _, err := NewPoint().ScalarMultCompressed(q, bigOrder.Bytes()); !errors.Is(err, ErrInfinityNotAllowed) {
+		t.Errorf("ScalarMultCompressed(n): got error %v", err)
+	}
+	if _, err := ScalarBaseMultCompressed(zero[1:]); err == nil {
+		t.Error("ScalarBaseMultCompressed accepted a short scalar")
+	}
+}
+
+func TestScalarMultReduce(t *testing.T) {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	q := NewPoint().ScalarBaseMultReduce(randomScalar(t))
+	nPlusOne := new(big.Int).Add(n, big.NewInt(1)).Bytes()
+	if got := NewPoint().ScalarMultReduce(q, nPlusOne); got.Equal(q) != 1 {
+		t.Errorf("(n+1) * q = %x, want %x", got.Bytes(), q.Bytes())
+	}
+	if got := NewPoint().ScalarMultReduce(q, n.Bytes()); got.Equal(NewPoint()) != 1 {
+		t.Errorf("n * q = %x, want infinity", got.Bytes())
+	}
+	if got := NewPoint().ScalarMultReduce(q, nil); got.Equal(NewPoint()) != 1 {
+		t.Errorf("empty scalar * q = %x, want infinity", got.Bytes())
+	}
+
+	k := randomScalar(t)
+	want, err := NewPoint().ScalarMult(q, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k48 := make([]byte, 48)
+	new(big.Int).Add(new(big.Int).Lsh(n, 100), new(big.Int).SetBytes(k)).FillBytes(k48)
+	if got := NewPoint().ScalarMultReduce(q, k48); got.Equal(want) != 1 {
+		t.Errorf("48-byte scalar: got %x, want %x", got.Bytes(), want.Bytes())
+	}
+	if got := NewPoint().ScalarMultReduce(q, k[1:]); got.Equal(NewPoint().ScalarMultReduce(q, append([]byte{0}, k[1:]...))) != 1 {
+		t.Error("short scalar is not left-padded")
+	}
+}
+
+func TestScalarMultWindow(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scalars := [][]byte{
+		append(make([]byte, 31), 1),
+		append(make([]byte, 31), 2),
+		append(bytes.Repeat([]byte{0xff}, 31), 0xfe),
+	}
+	for _, k := range testScalars(t) {
+		if len(k) == ElementLength {
+			scalars = append(scalars, k)
+		}
+	}
+	for w := 3; w <= 6; w++ {
+		for _, base := range []*Point{q, NewGenerator(), NewPoint()} {
+			for _, k := range scalars {
+				want, err := NewPoint().ScalarMult(base, k)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := NewPoint().ScalarMultWindow(base, k, w)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Equal(want) != 1 {
+					t.Errorf("w=%d, k=%x: got %x, want %x", w, k, got.Bytes(), want.Bytes())
+				}
+			}
+		}
+	}
+
+	// The receiver may overlap with the operand.
+	k := randomScalar(t)
+	want, _ := NewPoint().ScalarMult(q, k)
+	if got, _ := NewPoint().Set(q).ScalarMultWindow(q, k, 5); got.Equal(want) != 1 {
+		t.Errorf("aliased result differs")
+	}
+
+	for _, w := range []int{0, 2, 7} {
+		if _, err := NewPoint().ScalarMultWindow(q, k, w); err == nil {
+			t.Errorf("w=%d: expected error", w)
+		}
+	}
+	if _, err := NewPoint().ScalarMultWindow(q, k[:31], 4); err == nil {
+		t.Error("expected error for short scalar")
+	}
+}
+
+func BenchmarkScalarMultWindow(b *testing.B) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	k := randomScalar(b)
+	p := NewPoint()
+	for w := 3; w <= 6; w++ {
+		b.Run(fmt.Sprintf("w=%d", w), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.ScalarMultWindow(q, k, w)
+			}
+		})
+	}
+}
+
+func TestScalarMultLadder(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A base with Z ≠ 1.
+	q.Add(q, NewGenerator())
+	scalars := [][]byte{
+		nil, {0}, {1}, {2}, {0x80},
+		make([]byte, 32),
+		append(make([]byte, 31), 1),
+		orderMinusOne,
+		bigOrder.Bytes(),
+		new(big.Int).Add(bigOrder, big.NewInt(1)).Bytes(),
+		bytes.Repeat([]byte{0xff}, 32),
+		bytes.Repeat([]byte{0xff}, 48),
+	}
+	scalars = append(scalars, testScalars(t)...)
+	for i := 0; i < 32; i++ {
+		scalars = append(scalars, randomScalar(t))
+	}
+	for _, base := range []*Point{q, NewGenerator(), NewPoint()} {
+		for _, k := range scalars {
+			want, err := NewPoint().ScalarMult(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewPoint().ScalarMultLadder(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Equal(want) != 1 || got.IsOnCurve() != 1 {
+				t.Errorf("k=%x: got %v, want %v", k, got, want)
+			}
+		}
+	}
+
+	// The receiver may overlap with the operand.
+	k := randomScalar(t)
+	want, _ := NewPoint().ScalarMult(q, k)
+	got := NewPoint().Set(q)
+	if got.ScalarMultLadder(got, k); got.Equal(want) != 1 {
+		t.Errorf("aliased result differs")
+	}
+}
+
+func BenchmarkScalarMultLadder(b *testing.B) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	k := randomScalar(b)
+	p := NewPoint()
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(q, k)
+		}
+	})
+	b.Run("ScalarMultLadder", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.ScalarMultLadder(q, k)
+		}
+	})
+}
+
+func TestDoubleN(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A point with Z ≠ 1, and a non-canonical representation of infinity.
+	q.Add(q, NewGenerator())
+	inf := NewPoint().Add(NewGenerator(), NewPoint().Negate(NewGenerator()))
+	for _, base := range []*Point{NewGenerator(), q, NewPoint(), inf} {
+		want := NewPoint().Set(base)
+		for n := 0; n <= 8; n++ {
+			got := NewPoint().doubleN(base, n)
+			if !bytes.Equal(got.Bytes(), want.Bytes()) || got.IsOnCurve() != 1 {
+				t.Errorf("doubleN(%v, %d) = %v, want %v", base, n, got, want)
+			}
+			// The receiver may overlap with the operand.
+			if got := NewPoint().Set(base); !bytes.Equal(got.doubleN(got, n).Bytes(), want.Bytes()) {
+				t.Errorf("aliased doubleN(%v, %d) = %v, want %v", base, n, got, want)
+			}
+			want.Double(want)
+		}
+	}
+}
+
+func BenchmarkDoubleN(b *testing.B) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := NewPoint()
+	for _, n := range []int{4, 6, 8, 16} {
+		b.Run(fmt.Sprintf("Double/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.Set(q)
+				for j := 0; j < n; j++ {
+					p.Double(p)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("doubleN/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.doubleN(q, n)
+			}
+		})
+	}
+}
+
+func TestInfinity(t *testing.T) {
+	inf := NewPoint()
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pBytes := p.Bytes()
+	infBytes := []byte{0}
+
+	check := func(name string, got *Point, want []byte) {
+		t.Helper()
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("%s: got %x, want %x", name, got.Bytes(), want)
+		}
+	}
+
+	check("∞ + P", NewPoint().Add(inf, p), p.Bytes())
+	check("P + ∞", NewPoint().Add(p, inf), p.Bytes())
+	check("∞ + ∞", NewPoint().Add(inf, inf), infBytes)
+	check("P - P", NewPoint().Sub(p, p), infBytes)
+	check("P - ∞", NewPoint().Sub(p, inf), p.Bytes())
+	check("∞ - ∞", NewPoint().Sub(inf, inf), infBytes)
+	check("2∞", NewPoint().Double(inf), infBytes)
+
+	neg := NewPoint().Sub(inf, p)
+	check("P + (-P)", NewPoint().Add(p, neg), infBytes)
+	check("(-P) + P", NewPoint().Add(neg, p), infBytes)
+
+	check("P + P", NewPoint().Add(p, p), NewPoint().Double(p).Bytes())
+	check("(P + P) - P", NewPoint().Sub(NewPoint().Double(p), p), p.Bytes())
+
+	// The receiver may overlap with the operands.
+	q := NewPoint().Set(p)
+	check("q = q + q", q.Add(q, q), NewPoint().Double(p).Bytes())
+	q.Set(p)
+	check("q = q - q", q.Sub(q, q), infBytes)
+	q.Set(p)
+	check("q = 2q", q.Double(q), NewPoint().Double(p).Bytes())
+
+	// The operands must be left untouched.
+	check("P after operations", p, pBytes)
+	check("∞ after operations", inf, infBytes)
+}
+
+func TestValidatePublicKeyBytes(t *testing.T) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := [][]byte{p.Bytes(), p.BytesCompressed(), NewGenerator().Bytes(), NewGenerator().BytesCompressed()}
+	for i, b := range valid {
+		if err := ValidatePublicKeyBytes(b); err != nil {
+			t.Errorf("%d: unexpected error for %x: %v", i, b, err)
+		}
+	}
+
+	offCurve := p.Bytes()
+	offCurve[len(offCurve)-1] ^= 1
+	badPrefix := p.Bytes()
+	badPrefix[0] = 5
+	nonCanonical := p.Bytes()
+	copy(nonCanonical[1:], bytes.Repeat([]byte{0xff}, ElementLength))
+	// x = 5 is not the X coordinate of any point, as 5³ + 7 is not a square.
+	notSquare := append([]byte{2}, make([]byte, ElementLength)...)
+	notSquare[ElementLength] = 5
+
+	invalid := [][]byte{
+		nil,
+		{0},
+		offCurve,
+		badPrefix,
+		nonCanonical,
+		notSquare,
+		p.Bytes()[:64],
+		p.BytesCompressed()[:32],
+		append([]byte{3}, bytes.Repeat([]byte{0xff}, ElementLength)...),
+	}
+	for i, b := range invalid {
+		err := ValidatePublicKeyBytes(b)
+		if err == nil {
+			t.Errorf("%d: expected error for %x", i, b)
+		}
+		// ValidatePublicKeyBytes must agree with SetBytes, except for the
+		// point at infinity, which is not a valid public key.
+		if _, err := NewPoint().SetBytes(b); err == nil && len(b) != 1 {
+			t.Errorf("%d: SetBytes accepted %x", i, b)
+		}
+	}
+}
+
+func BenchmarkValidatePublicKey(b *testing.B) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, enc := range []struct {
+		name string
+		b    []byte
+	}{{"Uncompressed", p.Bytes()}, {"Compressed", p.BytesCompressed()}} {
+		b.Run(enc.name+"/SetBytes", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := NewPoint().SetBytes(enc.b); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(enc.name+"/ValidatePublicKeyBytes", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := ValidatePublicKeyBytes(enc.b); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSetBytesCompressed(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := NewPoint().SetBytes(p.BytesCompressed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(q.Bytes(), p.Bytes()) {
+			t.Errorf("%d: got %x, want %x", i, q.Bytes(), p.Bytes())
+		}
+	}
+}
+
+func TestNegate(t *testing.T) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewPoint().Add(p, NewPoint().Negate(q))
+	if want := NewPoint().Sub(p, q); !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("P + (-Q) = %x, want P - Q = %x", got.Bytes(), want.Bytes())
+	}
+	if got := NewPoint().Add(p, NewPoint().Negate(p)); !bytes.Equal(got.Bytes(), []byte{0}) {
+		t.Errorf("P + (-P) = %x, want ∞", got.Bytes())
+	}
+	if got := NewPoint().Negate(NewPoint()); !bytes.Equal(got.Bytes(), []byte{0}) {
+		t.Errorf("-∞ = %x, want ∞", got.Bytes())
+	}
+
+	// -(x, y) = (x, p - y), so the compressed encodings differ only in the
+	// sign byte.
+	neg := NewPoint().Negate(p).BytesCompressed()
+	want := p.BytesCompressed()
+	want[0] ^= 1
+	if !bytes.Equal(neg, want) {
+		t.Errorf("-P = %x, want %x", neg, want)
+	}
+
+	// The receiver may overlap with the operand.
+	r := NewPoint().Set(p)
+	if got := r.Negate(r).Negate(r); !bytes.Equal(got.Bytes(), p.Bytes()) {
+		t.Errorf("-(-P) = %x, want %x", got.Bytes(), p.Bytes())
+	}
+}
+
+// scaled returns a different projective representation of p, obtained by
+// multiplying all coordinates by a random non-zero λ.
+func scaled(t testing.TB, p *Point) *Point {
+	l := randomElement(t)
+	for l.IsZero() == 1 {
+		l = randomElement(t)
+	}
+	return &Point{
+		X: new(Element).Mul(p.X, l),
+		Y: new(Element).Mul(p.Y, l),
+		Z: new(Element).Mul(p.Z, l),
+	}
+}
+
+func TestPointEqual(t *testing.T) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inf := NewPoint()
+
+	tests := []struct {
+		name string
+		a, b *Point
+		want int
+	}{
+		{"P == P", p, p, 1},
+		{"P == λP", p, scaled(t, p), 1},
+		{"λP == λ'P", scaled(t, p), scaled(t, p), 1},
+		{"P == Q", p, q, 0},
+		{"P == -P", p, NewPoint().Negate(p), 0},
+		{"P == ∞", p, inf, 0},
+		{"∞ == P", inf, p, 0},
+		{"∞ == ∞", inf, NewPoint(), 1},
+		{"∞ == λ∞", inf, scaled(t, inf), 1},
+		{"∞ == -∞", inf, NewPoint().Negate(inf), 1},
+		{"∞ == P - P", inf, NewPoint().Sub(p, p), 1},
+		{"2P == P + P", NewPoint().Double(p), NewPoint().Add(p, p), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Equal(tt.b); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsInfinityIsOnCurve(t *testing.T) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offCurve := NewGenerator()
+	offCurve.Y.Add(offCurve.Y, new(Element).One())
+
+	tests := []struct {
+		name                string
+		p                   *Point
+		isInfinity, onCurve int
+	}{
+		{"G", NewGenerator(), 0, 1},
+		{"P", p, 0, 1},
+		{"λP", scaled(t, p), 0, 1},
+		{"∞", NewPoint(), 1, 1},
+		{"λ∞", scaled(t, NewPoint()), 1, 1},
+		{"P - P", NewPoint().Sub(p, p), 1, 1},
+		{"off-curve", offCurve, 0, 0},
+		{"(0:0:0)", &Point{new(Element), new(Element), new(Element)}, 1, 0},
+		{"(1:1:0)", &Point{new(Element).One(), new(Element).One(), new(Element)}, 1, 0},
+		{"(0:0:1)", &Point{new(Element), new(Element), new(Element).One()}, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.p.IsInfinity(); got != tt.isInfinity {
+			t.Errorf("%s: IsInfinity() = %d, want %d", tt.name, got, tt.isInfinity)
+		}
+		if got := tt.p.IsOnCurve(); got != tt.onCurve {
+			t.Errorf("%s: IsOnCurve() = %d, want %d", tt.name, got, tt.onCurve)
+		}
+	}
+}
+
+func TestXOnly(t *testing.T) {
+	for i := 0; i < 32; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := p.BytesXOnly()
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := NewPoint().SetBytesXOnly(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.BytesCompressed()[0] != 2 {
+			t.Errorf("SetBytesXOnly returned a point with odd Y")
+		}
+		if p.Equal(q) != 1 && p.Equal(NewPoint().Negate(q)) != 1 {
+			t.Errorf("SetBytesXOnly(%x) is neither p nor -p", b)
+		}
+		if p.BytesCompressed()[0] == 2 && p.Equal(q) != 1 {
+			t.Errorf("SetBytesXOnly(%x) did not round-trip an even-Y point", b)
+		}
+	}
+
+	if _, err := NewPoint().BytesXOnly(); err == nil {
+		t.Error("expected error encoding the point at infinity")
+	}
+	five := make([]byte, ElementLength)
+	five[ElementLength-1] = 5
+	p := NewGenerator()
+	for _, b := range [][]byte{five, decodeHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"), make([]byte, 31)} {
+		if _, err := p.SetBytesXOnly(b); err == nil {
+			t.Errorf("SetBytesXOnly(%x) succeeded", b)
+		}
+	}
+	if p.Equal(NewGenerator()) != 1 {
+		t.Error("failed SetBytesXOnly modified the receiver")
+	}
+}
+
+func TestLiftX(t *testing.T) {
+	tests := []struct {
+		x, y string // y is empty for invalid x
+	}{
+		// 0³ + 7 is not a square.
+		{"0000000000000000000000000000000000000000000000000000000000000000", ""},
+		{"0000000000000000000000000000000000000000000000000000000000000001",
+			"4218f20ae6c646b363db68605822fb14264ca8d2587fdd6fbc750d587e76a7ee"},
+		{"0000000000000000000000000000000000000000000000000000000000000003",
+			"d0dccc6a374f85c7cb5f1a6425bc6bb4a20c877ad1a9f143f0dd788060b640e4"},
+		// The generator has an even Y.
+		{"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+		// p - 1 and p - 2 are not X coordinates, p - 3 is.
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2e", ""},
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2d", ""},
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2c",
+			"f166b4eb158d073c146a38e1096da8a188afa7ccd281ad2f66a307fb778e45b2"},
+		// p + 1 is 1 modulo p, but x >= p is rejected.
+		{"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc30", ""},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", ""},
+	}
+	for _, tt := range tests {
+		p, err := LiftX(decodeHex(tt.x))
+		if tt.y == "" {
+			if err == nil {
+				t.Errorf("LiftX(%s) = %v, want error", tt.x, p)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LiftX(%s): %v", tt.x, err)
+			continue
+		}
+		want := append(append([]byte{4}, decodeHex(tt.x)...), decodeHex(tt.y)...)
+		if got := p.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("LiftX(%s) = %x, want %x", tt.x, got, want)
+		}
+	}
+	for _, n := range []int{0, 31, 33} {
+		if _, err := LiftX(make([]byte, n)); err == nil {
+			t.Errorf("LiftX accepted a %d-byte input", n)
+		}
+	}
+}
+
+func TestSetBytesHybrid(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hybrid := p.Bytes()
+		hybrid[0] = 6 | hybrid[len(hybrid)-1]&1
+		q, err := NewPoint().SetBytes(hybrid)
+		if err != nil {
+			t.Fatalf("SetBytes(%x): %v", hybrid, err)
+		}
+		if p.Equal(q) != 1 {
+			t.Errorf("SetBytes(%x) decoded a different point", hybrid)
+		}
+		if err := ValidatePublicKeyBytes(hybrid); err != nil {
+			t.Errorf("ValidatePublicKeyBytes(%x): %v", hybrid, err)
+		}
+
+		// Flip the parity in the type byte only.
+		hybrid[0] ^= 1
+		if _, err := NewPoint().SetBytes(hybrid); err == nil {
+			t.Errorf("SetBytes(%x) accepted a mismatched parity", hybrid)
+		}
+		if err := ValidatePublicKeyBytes(hybrid); err == nil {
+			t.Errorf("ValidatePublicKeyBytes(%x) accepted a mismatched parity", hybrid)
+		}
+	}
+}
+
+func TestAffine(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y, err := p.Affine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := p.Bytes()
+		if !bytes.Equal(x.Bytes(), b[1:1+ElementLength]) || !bytes.Equal(y.Bytes(), b[1+ElementLength:]) {
+			t.Errorf("Affine() = (%x, %x), want %x", x.Bytes(), y.Bytes(), b)
+		}
+	}
+	if _, _, err := NewPoint().Affine(); err == nil {
+		t.Error("expected error for the point at infinity")
+	}
+}
+
+func TestSetAffine(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y, err := p.Affine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := NewPoint().SetAffine(x, y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Equal(q) != 1 {
+			t.Errorf("SetAffine(Affine(p)) != p")
+		}
+
+		y.Add(y, new(Element).One())
+		r := NewGenerator()
+		if _, err := r.SetAffine(x, y); err == nil {
+			t.Errorf("SetAffine accepted an off-curve point")
+		}
+		if r.Equal(NewGenerator()) != 1 {
+			t.Errorf("failed SetAffine modified the receiver")
+		}
+	}
+	if _, err := NewPoint().SetAffine(new(Element), new(Element)); err == nil {
+		t.Errorf("SetAffine accepted (0, 0)")
+	}
+}
+
+func TestPointString(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		p    *Point
+		want string
+	}{
+		{"generator", NewGenerator(), "secp256k1: 0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		{"infinity", NewPoint(), "secp256k1: ∞"},
+		{"zero value", new(Point), "secp256k1: invalid point"},
+		{"nil", nil, "secp256k1: invalid point"},
+	} {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.String(), "secp256k1: "+hex.EncodeToString(p.BytesCompressed()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := fmt.Sprint(p); got != p.String() {
+		t.Errorf("fmt.Sprint = %q, want %q", got, p.String())
+	}
+}
+
+func TestCMov(t *testing.T) {
+	g := NewGenerator()
+	for _, tt := range []struct {
+		cond int
+		want *Point
+	}{
+		{0, NewPoint()},
+		{1, g},
+		{255, g},
+		{0x100, g},
+		{-1, g},
+		{1 << 30, g},
+	} {
+		p := NewPoint()
+		if got := p.CMov(g, tt.cond); got != p || p.Equal(tt.want) != 1 {
+			t.Errorf("CMov(G, %d) = %v, want %v", tt.cond, p, tt.want)
+		}
+		// The receiver may overlap with the operand.
+		if p.CMov(p, tt.cond); p.Equal(tt.want) != 1 {
+			t.Errorf("aliased CMov(%d) = %v, want %v", tt.cond, p, tt.want)
+		}
+	}
+}
+
+func TestSetGenerator(t *testing.T) {
+	want := decodeHex("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.SetGenerator(); got != p || !bytes.Equal(p.Bytes(), want) {
+		t.Errorf("SetGenerator() = %x, want %x", p.Bytes(), want)
+	}
+	if p.IsGenerator() != 1 {
+		t.Error("IsGenerator(G) = 0")
+	}
+	// Modifying p must not modify the package generator.
+	p.Double(p)
+	if !bytes.Equal(NewGenerator().Bytes(), want) {
+		t.Error("SetGenerator aliased the generator")
+	}
+
+	// A non-normalized representation of G.
+	q := NewPoint().Add(NewGenerator(), NewPoint())
+	q.Add(q, NewPoint().Double(NewGenerator()))
+	q.Sub(q, NewPoint().Double(NewGenerator()))
+	for _, tt := range []struct {
+		p    *Point
+		want int
+	}{
+		{q, 1},
+		{NewPoint(), 0},
+		{NewPoint().Negate(NewGenerator()), 0},
+		{NewPoint().Double(NewGenerator()), 0},
+	} {
+		if got := tt.p.IsGenerator(); got != tt.want {
+			t.Errorf("IsGenerator(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCombinedMult(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scalars [][]byte
+	for _, k := range testScalars(t) {
+		if len(k) == ElementLength {
+			scalars = append(scalars, k)
+		}
+	}
+	for i, s1 := range scalars {
+		s2 := scalars[len(scalars)-1-i]
+		for _, base := range []*Point{q, NewGenerator(), NewPoint()} {
+			want, err := NewPoint().ScalarBaseMult(s1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s2q, err := NewPoint().ScalarMult(base, s2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want.Add(want, s2q)
+
+			got, err := NewPoint().CombinedMult(base, s1, s2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Equal(want) != 1 {
+				t.Errorf("s1=%x, s2=%x: got %v, want %v", s1, s2, got, want)
+			}
+			// The receiver may overlap with q.
+			r := NewPoint().Set(base)
+			if r.CombinedMult(r, s1, s2); r.Equal(want) != 1 {
+				t.Errorf("s1=%x, s2=%x: aliased result differs", s1, s2)
+			}
+		}
+	}
+
+	if _, err := NewPoint().CombinedMult(q, make([]byte, 31), make([]byte, 32)); err == nil {
+		t.Error("expected error for short s1")
+	}
+}
+
+func TestSetBytesBatch(t *testing.T) {
+	var encodings [][]byte
+	var want []*Point
+	for i := 0; i < 8; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, p)
+		if i%2 == 0 {
+			encodings = append(encodings, p.BytesCompressed())
+		} else {
+			encodings = append(encodings, p.Bytes())
+		}
+	}
+	want = append(want, NewPoint())
+	encodings = append(encodings, []byte{0})
+
+	points := make([]*Point, len(encodings))
+	points[3] = NewGenerator()
+	if err := SetBytesBatch(points, encodings); err != nil {
+		t.Fatal(err)
+	}
+	for i := range points {
+		if points[i].Equal(want[i]) != 1 {
+			t.Errorf("%d: got %v, want %v", i, points[i], want[i])
+		}
+	}
+
+	// An invalid encoding is reported by index, and no point is modified.
+	bad := append([][]byte{}, encodings...)
+	bad[5] = append([]byte{2}, bytes.Repeat([]byte{0xff}, 32)...)
+	for i := range points {
+		points[i].SetGenerator()
+	}
+	err := SetBytesBatch(points, bad)
+	if err == nil || !strings.Contains(err.Error(), "index 5") {
+		t.Errorf("got error %v, want one mentioning index 5", err)
+	}
+	for i := range points {
+		if points[i].IsGenerator() != 1 {
+			t.Errorf("%d: point modified on error", i)
+		}
+	}
+
+	if err := SetBytesBatch(points[:1], encodings); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+}
+
+func BenchmarkSetBytesBatch(b *testing.B) {
+	const n = 1000
+	encodings := make([][]byte, n)
+	for i := range encodings {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(b))
+		if err != nil {
+			b.Fatal(err)
+		}
+		encodings[i] = p.BytesCompressed()
+	}
+	points := make([]*Point, n)
+	for i := range points {
+		points[i] = NewPoint()
+	}
+	b.Run("SetBytes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, enc := range encodings {
+				if _, err := points[j].SetBytes(enc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("SetBytesBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := SetBytesBatch(points, encodings); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestBytesFixed(t *testing.T) {
+	if b, err := NewPoint().BytesFixed(); err == nil || b != nil {
+		t.Errorf("BytesFixed(infinity) = %x, %v, want an error", b, err)
+	}
+	inf := NewPoint().Sub(NewGenerator(), NewGenerator())
+	if _, err := inf.BytesFixed(); err == nil {
+		t.Error("BytesFixed(G - G): expected error")
+	}
+	for i := 0; i < 4; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := p.BytesFixed()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 65 || !bytes.Equal(b, p.Bytes()) {
+			t.Errorf("BytesFixed() = %x, want %x", b, p.Bytes())
+		}
+	}
+}
+
+func TestBytesRaw64(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := p.BytesRaw64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, p.Bytes()[1:]) {
+			t.Errorf("BytesRaw64() = %x, want %x", b, p.Bytes()[1:])
+		}
+		q, err := NewPoint().SetBytesRaw64(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Equal(p) != 1 {
+			t.Errorf("SetBytesRaw64(%x) = %v, want %v", b, q, p)
+		}
+	}
+
+	if _, err := NewPoint().BytesRaw64(); !errors.Is(err, ErrInfinityNotAllowed) {
+		t.Errorf("BytesRaw64 of the point at infinity: got error %v", err)
+	}
+
+	offCurve := NewGenerator().Bytes()[1:]
+	offCurve[63] ^= 1
+	p := NewGenerator()
+	for _, tt := range []struct {
+		b    []byte
+		kind error
+	}{
+		{offCurve, ErrNotOnCurve},
+		{NewGenerator().Bytes(), ErrInvalidLength},
+		{NewGenerator().Bytes()[:63], ErrInvalidLength},
+		{append(P(), make([]byte, 32)...), ErrNonCanonical},
+	} {
+		if _, err := p.SetBytesRaw64(tt.b); !errors.Is(err, tt.kind) {
+			t.Errorf("SetBytesRaw64(%x): got error %v, want %v", tt.b, err, tt.kind)
+		}
+	}
+	if p.Equal(NewGenerator()) != 1 {
+		t.Error("failed SetBytesRaw64 modified the receiver")
+	}
+}
+
+func TestIsInSubgroup(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offCurve := NewGenerator()
+	offCurve.Y.Add(offCurve.Y, new(Element).One())
+	for _, tt := range []struct {
+		name string
+		p    *Point
+		want int
+	}{
+		{"generator", NewGenerator(), 1},
+		{"random", q, 1},
+		{"infinity", NewPoint(), 1},
+		{"non-normalized", NewPoint().Add(q, NewGenerator()), 1},
+		{"off curve", offCurve, 0},
+		{"(0:0:0)", &Point{new(Element), new(Element), new(Element)}, 0},
+		{"zero value", new(Point), 0},
+	} {
+		got := tt.p.IsInSubgroup()
+		if got != tt.want {
+			t.Errorf("%s: IsInSubgroup() = %d, want %d", tt.name, got, tt.want)
+		}
+		if tt.p.X != nil && got != tt.p.IsOnCurve() {
+			t.Errorf("%s: IsInSubgroup() disagrees with IsOnCurve()", tt.name)
+		}
+	}
+
+	// [n]P is the identity for every point, so the order divides n.
+	if nq, _ := NewPoint().ScalarMult(q, Order()); nq.IsInfinity() != 1 {
+		t.Errorf("[n]Q = %v, want infinity", nq)
+	}
+}
+
+func TestAppendBytes(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := []byte("prefix")
+	for _, p := range []*Point{NewPoint(), NewGenerator(), q} {
+		dst := append([]byte{}, prefix...)
+		if got := p.AppendBytes(dst); !bytes.Equal(got, append(prefix, p.Bytes()...)) {
+			t.Errorf("AppendBytes = %x, want %x", got, append(prefix, p.Bytes()...))
+		}
+		if got := p.AppendBytesCompressed(dst); !bytes.Equal(got, append(prefix, p.BytesCompressed()...)) {
+			t.Errorf("AppendBytesCompressed = %x, want %x", got, append(prefix, p.BytesCompressed()...))
+		}
+		if !bytes.Equal(dst, prefix) {
+			t.Errorf("dst modified: %q", dst)
+		}
+	}
+
+	buf := make([]byte, 0, 1+2*ElementLength)
+	if allocs := testing.AllocsPerRun(10, func() { buf = q.AppendBytes(buf[:0]) }); allocs > 0 {
+		t.Errorf("AppendBytes: %v allocations, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { buf = q.AppendBytesCompressed(buf[:0]) }); allocs > 0 {
+		t.Errorf("AppendBytesCompressed: %v allocations, want 0", allocs)
+	}
+}
+
+func BenchmarkBytes(b *testing.B) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf := make([]byte, 0, 1+2*ElementLength)
+	b.Run("Bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = p.Bytes()
+		}
+	})
+	b.Run("AppendBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = p.AppendBytes(buf[:0])
+		}
+	})
+	b.Run("BytesCompressed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = p.BytesCompressed()
+		}
+	})
+	b.Run("AppendBytesCompressed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = p.AppendBytesCompressed(buf[:0])
+		}
+	})
+}
+
+func BenchmarkAdd(b *testing.B) {
+	p, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	q := NewGenerator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Add(p, q)
+	}
+}
+
+func TestPointOperationsAllocations(t *testing.T) {
+	// The Element temporaries of the point formulas and the tables of the
+	// scalar multiplications are all kept on the stack, so the hot paths
+	// don't need a reusable workspace to avoid garbage.
+	k := randomScalar(t)
+	q, err := NewPoint().ScalarBaseMult(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPoint()
+	for _, tt := range []struct {
+		name string
+		f    func()
+	}{
+		{"Add", func() { p.Add(q, p) }},
+		{"Sub", func() { p.Sub(q, p) }},
+		{"Double", func() { p.Double(q) }},
+		{"ScalarMult", func() { p.ScalarMult(q, k) }},
+		{"ScalarBaseMult", func() { p.ScalarBaseMult(k) }},
+	} {
+		if allocs := testing.AllocsPerRun(10, tt.f); allocs > 0 {
+			t.Errorf("%s: %v allocations, want 0", tt.name, allocs)
+		}
+	}
+}
+
+func TestCheckOnCurve(t *testing.T) {
+	g := NewGenerator()
+	if err := CheckOnCurve(g.X, g.Y); err != nil {
+		t.Errorf("CheckOnCurve(G) = %v", err)
+	}
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := q.Bytes()
+	x, _ := new(Element).SetBytes(enc[1 : 1+ElementLength])
+	y, _ := new(Element).SetBytes(enc[1+ElementLength:])
+	if err := CheckOnCurve(x, y); err != nil {
+		t.Errorf("CheckOnCurve(%v) = %v", q, err)
+	}
+	// -Q is on the curve too.
+	if err := CheckOnCurve(x, new(Element).Sub(new(Element), y)); err != nil {
+		t.Errorf("CheckOnCurve(-Q) = %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		x, y *Element
+	}{
+		{"G with Y + 1", g.X, new(Element).Add(g.Y, new(Element).One())},
+		{"swapped", g.Y, g.X},
+		{"(0, 0)", new(Element), new(Element)},
+		{"projective infinity", new(Element), new(Element).One()},
+	} {
+		err := CheckOnCurve(tt.x, tt.y)
+		if !errors.Is(err, ErrNotOnCurve) {
+			t.Errorf("%s: CheckOnCurve = %v, want ErrNotOnCurve", tt.name, err)
+		}
+	}
+}
+
+func FuzzPointSetBytes(f *testing.F) {
+	g := NewGenerator()
+	q, err := NewPoint().ScalarBaseMult(randomScalar(f))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add([]byte{0})
+	f.Add([]byte{})
+	for _, p := range []*Point{g, q, NewPoint().Negate(q)} {
+		f.Add(p.Bytes())
+		f.Add(p.BytesCompressed())
+		hybrid := p.Bytes()
+		hybrid[0] = 6 | hybrid[64]&1
+		f.Add(hybrid)
+	}
+	f.Add(append([]byte{2}, P()...))
+	f.Add(append([]byte{4}, bytes.Repeat([]byte{0xff}, 64)...))
+	f.Add(append([]byte{3}, make([]byte, 32)...))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		p := NewGenerator()
+		if _, err := p.SetBytes(b); err != nil {
+			if p.Equal(g) != 1 || !bytes.Equal(p.Bytes(), g.Bytes()) {
+				t.Errorf("SetBytes(%x) failed but modified the receiver", b)
+			}
+			return
+		}
+		if p.IsOnCurve() != 1 {
+			t.Fatalf("SetBytes(%x) returned a point not on the curve", b)
+		}
+
+		var got []byte
+		switch b[0] {
+		case 0, 4:
+			got = p.Bytes()
+		case 2, 3:
+			got = p.BytesCompressed()
+		case 6, 7:
+			got = p.Bytes()
+			got[0] = 6 | got[64]&1
+		default:
+			t.Fatalf("SetBytes(%x) accepted an unknown type byte", b)
+		}
+		if !bytes.Equal(got, b) {
+			t.Errorf("SetBytes(%x) re-encodes to %x", b, got)
+		}
+	})
+}
+
+// quickPoint is a random point for testing/quick. One in eight is the point at
+// infinity, and the others are random multiples of G with a random Z.
+type quickPoint struct{ *Point }
+
+func (quickPoint) Generate(r *mathrand.Rand, size int) reflect.Value {
+	if r.Intn(8) == 0 {
+		return reflect.ValueOf(quickPoint{NewPoint()})
+	}
+	k := make([]byte, ElementLength)
+	r.Read(k)
+	p, err := NewPoint().ScalarBaseMult(k)
+	if err != nil {
+		panic(err)
+	}
+	l := make([]byte, ElementLength)
+	r.Read(l)
+	l[0] &= 0x7f
+	l[ElementLength-1] |= 1
+	z, err := new(Element).SetBytes(l)
+	if err != nil {
+		panic(err)
+	}
+	p.X.Mul(p.X, z)
+	p.Y.Mul(p.Y, z)
+	p.Z.Mul(p.Z, z)
+	return reflect.ValueOf(quickPoint{p})
+}
+
+func TestPointGroupLaws(t *testing.T) {
+	inf := NewPoint()
+	laws := map[string]interface{}{
+		"associativity": func(a, b, c quickPoint) bool {
+			lhs := NewPoint().Add(NewPoint().Add(a.Point, b.Point), c.Point)
+			rhs := NewPoint().Add(a.Point, NewPoint().Add(b.Point, c.Point))
+			return lhs.Equal(rhs) == 1
+		},
+		"commutativity": func(a, b quickPoint) bool {
+			return NewPoint().Add(a.Point, b.Point).Equal(NewPoint().Add(b.Point, a.Point)) == 1
+		},
+		"identity": func(a quickPoint) bool {
+			return NewPoint().Add(a.Point, inf).Equal(a.Point) == 1 &&
+				NewPoint().Add(inf, a.Point).Equal(a.Point) == 1
+		},
+		"inverse": func(a quickPoint) bool {
+			neg := NewPoint().Negate(a.Point)
+			return NewPoint().Add(a.Point, neg).IsInfinity() == 1 &&
+				NewPoint().Add(neg, a.Point).IsInfinity() == 1 &&
+				NewPoint().Sub(a.Point, a.Point).IsInfinity() == 1
+		},
+		"doubling": func(a quickPoint) bool {
+			return NewPoint().Add(a.Point, a.Point).Equal(NewPoint().Double(a.Point)) == 1
+		},
+		"aliasing": func(a, b quickPoint) bool {
+			want := NewPoint().Add(a.Point, b.Point)
+			got := NewPoint().Set(a.Point)
+			return got.Add(got, b.Point).Equal(want) == 1 && got.IsOnCurve() == 1
+		},
+	}
+	for name, law := range laws {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(law, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
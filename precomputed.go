@@ -0,0 +1,33 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+// Precomputed holds the table of multiples of a fixed point, so that it can be
+// multiplied by many scalars without recomputing the table every time, as
+// Point.ScalarMult does.
+//
+// A Precomputed is safe for concurrent use by multiple goroutines.
+type Precomputed struct {
+	table table
+}
+
+// NewPrecomputed returns the precomputed table for q. Later changes to q don't
+// affect the returned value.
+func NewPrecomputed(q *Point) *Precomputed {
+	pc := &Precomputed{}
+	pc.table[0] = NewPoint().Set(q)
+	for i := 1; i < 15; i += 2 {
+		pc.table[i] = NewPoint().Double(pc.table[i/2])
+		pc.table[i+1] = NewPoint().Add(pc.table[i], q)
+	}
+	return pc
+}
+
+// ScalarMult returns scalar * q, where q is the point pc was computed for. It
+// accepts the same scalars as Point.ScalarMult, and like it runs in constant
+// time with respect to the value of scalar.
+func (pc *Precomputed) ScalarMult(scalar []byte) (*Point, error) {
+	return NewPoint().scalarMultTable(&pc.table, scalar), nil
+}
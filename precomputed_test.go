@@ -0,0 +1,67 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import "testing"
+
+func TestPrecomputed(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []*Point{q, NewGenerator(), NewPoint()} {
+		pc := NewPrecomputed(base)
+		for _, k := range testScalars(t) {
+			want, err := NewPoint().ScalarMult(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := pc.ScalarMult(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Equal(want) != 1 {
+				t.Errorf("k=%x: got %v, want %v", k, got, want)
+			}
+		}
+	}
+
+	// The table doesn't alias the point it was computed from.
+	p := NewGenerator()
+	pc := NewPrecomputed(p)
+	p.Double(p)
+	if got, _ := pc.ScalarMult([]byte{1}); got.Equal(NewGenerator()) != 1 {
+		t.Errorf("got %v after modifying the base, want the generator", got)
+	}
+}
+
+func BenchmarkPrecomputed(b *testing.B) {
+	// 1000 multiplications of the same base, as for a fixed recipient key.
+	const n = 1000
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	scalars := make([][]byte, n)
+	for i := range scalars {
+		scalars[i] = randomScalar(b)
+	}
+	p := NewPoint()
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, k := range scalars {
+				p.ScalarMult(q, k)
+			}
+		}
+	})
+	b.Run("Precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			pc := NewPrecomputed(q)
+			for _, k := range scalars {
+				pc.ScalarMult(k)
+			}
+		}
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/wdvxdr1123/secp256k1/internal/wifcodec"
+)
+
+// NewPrivateKeyFromHex decodes a private key encoded as 64 hexadecimal digits
+// and returns the 32-byte big-endian scalar. The scalar must be in [1, n-1].
+func NewPrivateKeyFromHex(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secp256k1 private key: %w", err)
+	}
+	if err := checkPrivateKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NewPrivateKeyFromWIF decodes a private key in Bitcoin's Wallet Import Format
+// and returns the 32-byte big-endian scalar, which must be in [1, n-1].
+//
+// Both the mainnet (0x80) and testnet (0xef) version bytes are accepted. Use
+// wif.DecodeWIF to also learn which one the key carries.
+// compressed reports whether the key carries the 0x01 suffix, which marks it as
+// corresponding to a compressed public key.
+func NewPrivateKeyFromWIF(s string) (key []byte, compressed bool, err error) {
+	key, compressed, _, err = wifcodec.Decode(s)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid WIF private key: %w", err)
+	}
+	if err := checkPrivateKey(key); err != nil {
+		return nil, false, err
+	}
+	return key, compressed, nil
+}
+
+// PrivateKeyFromBytes deterministically maps the entropy b, of any length, to
+// a private key, by reducing it modulo n as a big-endian integer, and returns
+// the 32-byte big-endian scalar. It returns an error only if the result is
+// zero, which for uniformly random input is astronomically unlikely.
+//
+// Unlike NewPrivateKeyFromHex, which rejects scalars out of range, any input
+// is accepted. To make the bias of the reduction negligible, b should be at
+// least 40 bytes long, such as a 64-byte seed or hash output, as recommended
+// by FIPS 186-5, Appendix A.2.1. PrivateKeyFromBytes runs in constant time
+// with respect to the value of b, for a given length.
+func PrivateKeyFromBytes(b []byte) ([]byte, error) {
+	s := new(Scalar).SetBytesReduce(b)
+	if s.IsZero() == 1 {
+		return nil, errors.New("invalid secp256k1 private key")
+	}
+	return s.Bytes(), nil
+}
+
+// checkPrivateKey returns an error if key is not a 32-byte encoding of a
+// scalar in [1, n-1].
+func checkPrivateKey(key []byte) error {
+	if !ValidPrivateKey(key) {
+		return errors.New("invalid secp256k1 private key")
+	}
+	return nil
+}
+
+// ValidPrivateKey reports whether scalar is a valid private key, that is a
+// 32-byte big-endian integer in [1, n-1], where n is the order of the group.
+// It runs in constant time with respect to the value of scalar.
+func ValidPrivateKey(scalar []byte) bool {
+	if len(scalar) != ElementLength {
+		return false
+	}
+	var acc byte
+	for _, b := range scalar {
+		acc |= b
+	}
+	nonZero := 1 ^ subtle.ConstantTimeByteEq(acc, 0)
+	return nonZero&lessThanOrder(scalar) == 1
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1/internal/base58"
+	"github.com/wdvxdr1123/secp256k1/internal/wifcodec"
+)
+
+func TestNewPrivateKeyFromHex(t *testing.T) {
+	key, err := NewPrivateKeyFromHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := decodeHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"); !bytes.Equal(key, want) {
+		t.Errorf("got %x, want %x", key, want)
+	}
+
+	for _, s := range []string{
+		"",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa",
+		"0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1",
+		"zz28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+	} {
+		if _, err := NewPrivateKeyFromHex(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestNewPrivateKeyFromWIF(t *testing.T) {
+	tests := []struct {
+		wif        string
+		key        string
+		compressed bool
+	}{
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", false},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", true},
+		{"5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", "0000000000000000000000000000000000000000000000000000000000000001", false},
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", "0000000000000000000000000000000000000000000000000000000000000001", true},
+		{"91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", false},
+		{"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", "0000000000000000000000000000000000000000000000000000000000000001", true},
+	}
+	for i, tt := range tests {
+		key, compressed, err := NewPrivateKeyFromWIF(tt.wif)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(key, decodeHex(tt.key)) || compressed != tt.compressed {
+			t.Errorf("%d: got %x, %v, want %s, %v", i, key, compressed, tt.key, tt.compressed)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		// Bad checksum.
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTK",
+		// Invalid base58 character.
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvy0J",
+		// A P2PKH address: valid base58check, wrong length.
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		// Unknown version byte.
+		base58.CheckEncode(append([]byte{0x00}, orderMinusOne...)),
+		// Bad compression flag.
+		base58.CheckEncode(append(append([]byte{wifcodec.MainnetVersion}, orderMinusOne...), 0x02)),
+		// Zero and out-of-range scalars.
+		base58.CheckEncode(append([]byte{wifcodec.MainnetVersion}, make([]byte, ElementLength)...)),
+		base58.CheckEncode(append([]byte{wifcodec.MainnetVersion}, bytes.Repeat([]byte{0xff}, ElementLength)...)),
+	} {
+		if _, _, err := NewPrivateKeyFromWIF(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestValidPrivateKey(t *testing.T) {
+	for _, tt := range []struct {
+		key  string
+		want bool
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"0000000000000000000000000000000000000000000000000000000000000001", true},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", true},  // n - 1
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", false}, // n
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364142", false}, // n + 1
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", false},
+		{"01", false},
+		{"000000000000000000000000000000000000000000000000000000000000000001", false},
+	} {
+		if got := ValidPrivateKey(decodeHex(tt.key)); got != tt.want {
+			t.Errorf("ValidPrivateKey(%s) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPrivateKeyFromBytes(t *testing.T) {
+	seed := make([]byte, 64)
+	for i := 0; i < 32; i++ {
+		if _, err := rand.Read(seed); err != nil {
+			t.Fatal(err)
+		}
+		key, err := PrivateKeyFromBytes(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ValidPrivateKey(key) {
+			t.Errorf("PrivateKeyFromBytes(%x) = %x is not a valid key", seed, key)
+		}
+		want := new(big.Int).Mod(new(big.Int).SetBytes(seed), bigOrder)
+		if new(big.Int).SetBytes(key).Cmp(want) != 0 {
+			t.Errorf("PrivateKeyFromBytes(%x) = %x, want %x", seed, key, want)
+		}
+		if again, _ := PrivateKeyFromBytes(seed); !bytes.Equal(again, key) {
+			t.Error("PrivateKeyFromBytes is not deterministic")
+		}
+	}
+
+	// In-range input is returned as is, and short input is zero-extended.
+	if key, err := PrivateKeyFromBytes(orderMinusOne); err != nil || !bytes.Equal(key, orderMinusOne) {
+		t.Errorf("PrivateKeyFromBytes(n - 1) = %x, %v", key, err)
+	}
+	if key, err := PrivateKeyFromBytes([]byte{1}); err != nil || !bytes.Equal(key, decodeHex("0000000000000000000000000000000000000000000000000000000000000001")) {
+		t.Errorf("PrivateKeyFromBytes(1) = %x, %v", key, err)
+	}
+	for _, b := range [][]byte{nil, make([]byte, 64), bigOrder.Bytes(), new(big.Int).Lsh(bigOrder, 100).Bytes()} {
+		if key, err := PrivateKeyFromBytes(b); err == nil {
+			t.Errorf("PrivateKeyFromBytes(%x) = %x, want an error", b, key)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ring implements Abe-Ohkubo-Suzuki (AOS) ring signatures over
+// secp256k1, the construction Borromean ring signatures are built from.
+//
+// A ring signature proves that the signer knows the private key of one of a
+// set of public keys, without revealing which one.
+package ring
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// RingSignature is an AOS ring signature over a ring of n public keys.
+type RingSignature struct {
+	// E is the challenge at ring position zero.
+	E secp256k1.Scalar
+	// S holds the response for each position of the ring.
+	S []secp256k1.Scalar
+}
+
+// Sign produces a ring signature of msg over the ring pubs, using the private
+// key priv, whose public key must be pubs[keyIndex].
+func Sign(priv []byte, keyIndex int, pubs []*secp256k1.Point, msg []byte) (*RingSignature, error) {
+	if keyIndex < 0 || keyIndex >= len(pubs) {
+		return nil, errors.New("ring: key index out of range")
+	}
+	if !validRing(pubs) {
+		return nil, errors.New("ring: invalid ring member")
+	}
+	x, err := new(secp256k1.Scalar).SetBytes(priv)
+	if err != nil || x.IsZero() == 1 {
+		return nil, errors.New("ring: invalid private key")
+	}
+	pub, err := secp256k1.NewPoint().ScalarBaseMult(priv)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(pub.BytesCompressed(), pubs[keyIndex].BytesCompressed()) != 1 {
+		return nil, errors.New("ring: private key does not match the public key at key index")
+	}
+
+	prefix := ringPrefix(pubs, msg)
+	n := len(pubs)
+	sig := &RingSignature{S: make([]secp256k1.Scalar, n)}
+	es := make([]secp256k1.Scalar, n)
+
+	// The ring is closed at the signer's position: start from a commitment
+	// [α]G and walk around the ring with random responses.
+	alpha, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	r, err := secp256k1.NewPoint().ScalarBaseMult(alpha.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	es[(keyIndex+1)%n].Set(challenge(prefix, r))
+	for i := (keyIndex + 1) % n; i != keyIndex; i = (i + 1) % n {
+		s, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		sig.S[i].Set(s)
+		r, err := commitment(s, &es[i], pubs[i])
+		if err != nil {
+			return nil, err
+		}
+		es[(i+1)%n].Set(challenge(prefix, r))
+	}
+
+	// s = α - e·x, so that [s]G + [e]P = [α]G.
+	ex := new(secp256k1.Scalar).Mul(&es[keyIndex], x)
+	sig.S[keyIndex].Sub(alpha, ex)
+	sig.E.Set(&es[0])
+	return sig, nil
+}
+
+// Verify reports whether sig is a valid ring signature of msg by the holder of
+// a private key for one of pubs.
+func Verify(sig *RingSignature, pubs []*secp256k1.Point, msg []byte) bool {
+	if sig == nil || len(pubs) == 0 || len(sig.S) != len(pubs) || !validRing(pubs) {
+		return false
+	}
+	prefix := ringPrefix(pubs, msg)
+	e := new(secp256k1.Scalar).Set(&sig.E)
+	for i := range pubs {
+		r, err := commitment(&sig.S[i], e, pubs[i])
+		if err != nil {
+			return false
+		}
+		e.Set(challenge(prefix, r))
+	}
+	return e.Equal(&sig.E) == 1
+}
+
+// validRing reports whether every member of pubs is a non-nil point other than
+// the point at infinity. An identity member would let anyone close the ring at
+// its position without knowing any private key, since [e]P contributes nothing
+// to the commitment.
+func validRing(pubs []*secp256k1.Point) bool {
+	for _, p := range pubs {
+		if p == nil || p.IsInfinity() == 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// commitment returns [s]G + [e]P.
+func commitment(s, e *secp256k1.Scalar, p *secp256k1.Point) (*secp256k1.Point, error) {
+	sg, err := secp256k1.NewPoint().ScalarBaseMult(s.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ep, err := secp256k1.NewPoint().ScalarMult(p, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return sg.Add(sg, ep), nil
+}
+
+// ringPrefix commits to the whole ring and the message, so that a signature
+// can't be transplanted to a different ring or message.
+func ringPrefix(pubs []*secp256k1.Point, msg []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("secp256k1/ring"))
+	for _, p := range pubs {
+		h.Write(p.BytesCompressed())
+	}
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// challenge hashes the ring prefix and a commitment point to a scalar. Digests
+// that are not valid scalars are rejected and rehashed with a counter, which
+// happens with probability less than 2⁻¹²⁷.
+func challenge(prefix []byte, r *secp256k1.Point) *secp256k1.Scalar {
+	for ctr := byte(0); ; ctr++ {
+		h := sha256.New()
+		h.Write(prefix)
+		h.Write(r.BytesCompressed())
+		h.Write([]byte{ctr})
+		if e, err := new(secp256k1.Scalar).SetBytes(h.Sum(nil)); err == nil {
+			return e
+		}
+	}
+}
+
+// randomScalar returns a uniformly random non-zero scalar.
+func randomScalar() (*secp256k1.Scalar, error) {
+	var buf [secp256k1.ElementLength]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		s, err := new(secp256k1.Scalar).SetBytes(buf[:])
+		if err == nil && s.IsZero() == 0 {
+			return s, nil
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ring
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func generateRing(t *testing.T, n int) ([][]byte, []*secp256k1.Point) {
+	var privs [][]byte
+	var pubs []*secp256k1.Point
+	for i := 0; i < n; i++ {
+		priv := make([]byte, secp256k1.ElementLength)
+		if _, err := rand.Read(priv); err != nil {
+			t.Fatal(err)
+		}
+		priv[0] &= 0x7f
+		pub, err := secp256k1.NewPoint().ScalarBaseMult(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privs = append(privs, priv)
+		pubs = append(pubs, pub)
+	}
+	return privs, pubs
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("hello, ring")
+	for _, n := range []int{1, 2, 3, 8} {
+		privs, pubs := generateRing(t, n)
+		for i := range privs {
+			sig, err := Sign(privs[i], i, pubs, msg)
+			if err != nil {
+				t.Fatalf("n=%d, i=%d: %v", n, i, err)
+			}
+			if !Verify(sig, pubs, msg) {
+				t.Errorf("n=%d, i=%d: valid signature rejected", n, i)
+			}
+			if Verify(sig, pubs, []byte("another message")) {
+				t.Errorf("n=%d, i=%d: signature accepted for a different message", n, i)
+			}
+		}
+	}
+}
+
+func TestForgery(t *testing.T) {
+	msg := []byte("hello, ring")
+	privs, pubs := generateRing(t, 4)
+	sig, err := Sign(privs[2], 2, pubs, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tamper with a response.
+	forged := &RingSignature{E: sig.E, S: append([]secp256k1.Scalar{}, sig.S...)}
+	forged.S[1].Add(&forged.S[1], new(secp256k1.Scalar).One())
+	if Verify(forged, pubs, msg) {
+		t.Error("signature with tampered response accepted")
+	}
+
+	// Tamper with the challenge.
+	forged = &RingSignature{E: sig.E, S: sig.S}
+	forged.E.Add(&forged.E, new(secp256k1.Scalar).One())
+	if Verify(forged, pubs, msg) {
+		t.Error("signature with tampered challenge accepted")
+	}
+
+	// Replace a ring member.
+	_, others := generateRing(t, 1)
+	ring := append([]*secp256k1.Point{}, pubs...)
+	ring[0] = others[0]
+	if Verify(sig, ring, msg) {
+		t.Error("signature accepted for a different ring")
+	}
+
+	// Truncate the ring.
+	if Verify(sig, pubs[:3], msg) {
+		t.Error("signature accepted for a truncated ring")
+	}
+
+	// Sign with a key that is not at the claimed position.
+	if _, err := Sign(privs[0], 1, pubs, msg); err == nil {
+		t.Error("expected error signing with a key not in the ring position")
+	}
+}
+
+func TestInvalidMembers(t *testing.T) {
+	msg := []byte("hello, ring")
+	privs, pubs := generateRing(t, 2)
+
+	// With the identity in the ring, [s]G + [e]∞ = [s]G for any e, so the
+	// ring could be closed at that position without a private key. Build such
+	// a forgery by hand and check it is rejected, along with signing over it.
+	ring := []*secp256k1.Point{pubs[0], secp256k1.NewPoint()}
+	if _, err := Sign(privs[0], 0, ring, msg); err == nil {
+		t.Error("expected error signing over a ring with the identity")
+	}
+	alpha, err := randomScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s0, err := randomScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := ringPrefix(ring, msg)
+	r, err := secp256k1.NewPoint().ScalarBaseMult(alpha.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e0 := challenge(prefix, r)
+	r, err = commitment(s0, e0, ring[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	e1 := challenge(prefix, r)
+	if r, err = commitment(alpha, e1, ring[1]); err != nil {
+		t.Fatal(err)
+	}
+	if challenge(prefix, r).Equal(e0) != 1 {
+		t.Fatal("forged ring does not close")
+	}
+	forged := &RingSignature{E: *e0, S: []secp256k1.Scalar{*s0, *alpha}}
+	if Verify(forged, ring, msg) {
+		t.Error("forged signature accepted for a ring with the identity")
+	}
+
+	// A nil member must be rejected rather than cause a panic.
+	sig, err := Sign(privs[0], 0, pubs, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Verify(sig, []*secp256k1.Point{pubs[0], nil}, msg) {
+		t.Error("signature accepted for a ring with a nil member")
+	}
+	if _, err := Sign(privs[0], 0, []*secp256k1.Point{pubs[0], nil}, msg); err == nil {
+		t.Error("expected error signing over a ring with a nil member")
+	}
+}
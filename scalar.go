@@ -0,0 +1,365 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// order is the order n of the secp256k1 group as little-endian 64-bit limbs.
+var order = [4]uint64{0xbfd25e8cd0364141, 0xbaaedce6af48a03b, 0xfffffffffffffffe, 0xffffffffffffffff}
+
+// orderInv is -n⁻¹ mod 2^64, used by the Montgomery reduction.
+const orderInv = 0x4b0dff665588b13f
+
+// halfOrder is (n - 1) / 2 as little-endian 64-bit limbs.
+var halfOrder = [4]uint64{0xdfe92f46681b20a0, 0x5d576e7357a4501d, 0xffffffffffffffff, 0x7fffffffffffffff}
+
+// orderR2 is 2^512 mod n, used to convert into the Montgomery domain.
+var orderR2 = Scalar{0x896cf21467d7d140, 0x741496c20e7cf878, 0xe697f5e45bcd07c6, 0x9d671cd581c69bc5}
+
+// Order returns the 32-byte big-endian encoding of the order of the secp256k1
+// group, n = 0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141.
+// Valid scalars and private keys are lower than n. The returned slice is a
+// fresh copy.
+func Order() []byte {
+	return limbsBytes(&order)
+}
+
+// HalfOrder returns the 32-byte big-endian encoding of (n - 1) / 2, where n is
+// the order of the secp256k1 group. It is the low-S threshold: a signature
+// value s is high if it is greater than HalfOrder, as reported by
+// Scalar.IsHigh. The returned slice is a fresh copy.
+func HalfOrder() []byte {
+	return limbsBytes(&halfOrder)
+}
+
+// Scalar is an integer modulo n = 2^256 - 432420386565659656852420866394968145599,
+// the order of the secp256k1 group.
+//
+// The zero value is a valid zero scalar.
+type Scalar [4]uint64
+
+// One sets s = 1, and returns s.
+func (s *Scalar) One() *Scalar {
+	s[0] = 0x402da1732fc9bebf
+	s[1] = 0x4551231950b75fc4
+	s[2] = 0x1
+	s[3] = 0x0
+	return s
+}
+
+// Set sets s = t, and returns s.
+func (s *Scalar) Set(t *Scalar) *Scalar {
+	*s = *t
+	return s
+}
+
+// Equal returns 1 if s == t, and zero otherwise.
+func (s *Scalar) Equal(t *Scalar) int {
+	// Scalars are always fully reduced, so their Montgomery representation is
+	// unique and can be compared limb by limb.
+	var acc uint64
+	for i := range s {
+		acc |= s[i] ^ t[i]
+	}
+	return int((acc|-acc)>>63) ^ 1
+}
+
+// IsZero returns 1 if s == 0, and zero otherwise.
+func (s *Scalar) IsZero() int {
+	return s.Equal(new(Scalar))
+}
+
+// Wipe sets s = 0, to clear secret values from memory when they are no longer
+// needed.
+//
+// Wipe is best-effort: the compiler and the garbage collector may have left
+// other copies of the value, for example in spilled registers or in memory
+// that was moved.
+func (s *Scalar) Wipe() {
+	*s = Scalar{}
+}
+
+// Select sets s to a if cond == 1, and to b if cond == 0.
+func (s *Scalar) Select(a, b *Scalar, cond int) *Scalar {
+	condition := uint64(cond)
+	s[0] = cmovznz(condition, b[0], a[0])
+	s[1] = cmovznz(condition, b[1], a[1])
+	s[2] = cmovznz(condition, b[2], a[2])
+	s[3] = cmovznz(condition, b[3], a[3])
+	return s
+}
+
+// A scalarTable holds the first 15 multiples of a scalar at offset -1, so
+// [1]s is at table[0], [15]s is at table[14], and [0]s is implicitly zero,
+// like the point table.
+type scalarTable [15]*Scalar
+
+// newScalarTable returns the table of multiples of s.
+func newScalarTable(s *Scalar) *scalarTable {
+	t := new(scalarTable)
+	t[0] = new(Scalar).Set(s)
+	for i := 1; i < 15; i++ {
+		t[i] = new(Scalar).Add(t[i-1], s)
+	}
+	return t
+}
+
+// Select selects the n-th multiple of the table base scalar into s. It works in
+// constant time by iterating over every entry of the table. n must be in [0, 15].
+func (table *scalarTable) Select(s *Scalar, n uint8) {
+	if n >= 16 {
+		panic("secp256k1: internal error: scalarTable called with out-of-bounds value")
+	}
+	*s = Scalar{}
+	for i := uint8(1); i < 16; i++ {
+		cond := subtle.ConstantTimeByteEq(i, n)
+		s.Select(table[i-1], s, cond)
+	}
+}
+
+// Bytes returns the 32-byte big-endian encoding of s.
+func (s *Scalar) Bytes() []byte {
+	// This function is outlined to make the allocations inline in the caller
+	// rather than happen on the heap.
+	var out [ElementLength]byte
+	return s.bytes(&out)
+}
+
+func (s *Scalar) bytes(out *[ElementLength]byte) []byte {
+	var tmp Scalar
+	scalarMontMul(&tmp, s, &Scalar{1})
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint64(out[ElementLength-8*(i+1):], tmp[i])
+	}
+	return out[:]
+}
+
+// SetBytes sets s = v, where v is a big-endian 32-byte encoding, and returns s.
+// If v is not 32 bytes or it encodes a value higher than or equal to n,
+// SetBytes returns nil and an error, and s is unchanged.
+func (s *Scalar) SetBytes(v []byte) (*Scalar, error) {
+	if len(v) != ElementLength {
+		return nil, errors.New("invalid Scalar encoding")
+	}
+	if lessThanOrder(v) != 1 {
+		return nil, errors.New("invalid Scalar encoding")
+	}
+	var tmp Scalar
+	for i := 0; i < 4; i++ {
+		tmp[i] = binary.BigEndian.Uint64(v[ElementLength-8*(i+1):])
+	}
+	scalarMontMul(s, &tmp, &orderR2)
+	return s, nil
+}
+
+// SetBytesReduce sets s = v mod n, where v is a big-endian encoding of any
+// length, and returns s.
+//
+// Unlike SetBytes, it never fails, which makes it suitable for deriving
+// scalars from hash outputs. It runs in constant time with respect to the
+// value of v, for a given length.
+func (s *Scalar) SetBytesReduce(v []byte) *Scalar {
+	acc := new(Scalar)
+	var chunk [ElementLength]byte
+	var c Scalar
+	for len(v) > 0 {
+		// Consume the most significant chunk, so that every following chunk is
+		// exactly 32 bytes long.
+		k := len(v) % ElementLength
+		if k == 0 {
+			k = ElementLength
+		}
+		chunk = [ElementLength]byte{}
+		copy(chunk[ElementLength-k:], v[:k])
+		v = v[k:]
+
+		for i := 0; i < 4; i++ {
+			c[i] = binary.BigEndian.Uint64(chunk[ElementLength-8*(i+1):])
+		}
+		reduceOnce(&c)
+
+		// acc = acc * 2^256 + chunk. In the Montgomery domain, multiplying by
+		// R² = 2^512 mod n shifts left by 256 bits for acc, and converts the
+		// plain chunk into the Montgomery domain.
+		scalarMontMul(acc, acc, &orderR2)
+		scalarMontMul(&c, &c, &orderR2)
+		acc.Add(acc, &c)
+	}
+	return s.Set(acc)
+}
+
+// HashToScalar converts a message digest of any length to a scalar, as
+// specified for ECDSA in SEC 1, Version 2.0, Section 4.1.3, point 5, and by
+// bits2int in RFC 6979, Section 2.3.2: the leftmost 256 bits of hash are taken
+// as a big-endian integer, which is then reduced modulo n. Shorter digests are
+// not shifted, so they are interpreted as their integer value.
+//
+// The truncation happens before the reduction, so HashToScalar differs from
+// SetBytesReduce for digests longer than 32 bytes.
+func HashToScalar(hash []byte) *Scalar {
+	if len(hash) > ElementLength {
+		hash = hash[:ElementLength]
+	}
+	return new(Scalar).SetBytesReduce(hash)
+}
+
+// reduceOnce subtracts n from the plain 256-bit value c if c >= n. Since
+// 2^256 < 2n, the result is always fully reduced.
+func reduceOnce(c *Scalar) {
+	var diff [4]uint64
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		diff[i], borrow = bits.Sub64(c[i], order[i], borrow)
+	}
+	for i := 0; i < 4; i++ {
+		c[i] = cmovznz(borrow, diff[i], c[i])
+	}
+}
+
+// Add sets s = t1 + t2, and returns s.
+func (s *Scalar) Add(t1, t2 *Scalar) *Scalar {
+	var sum, diff [4]uint64
+	var carry, borrow uint64
+	for i := 0; i < 4; i++ {
+		sum[i], carry = bits.Add64(t1[i], t2[i], carry)
+	}
+	for i := 0; i < 4; i++ {
+		diff[i], borrow = bits.Sub64(sum[i], order[i], borrow)
+	}
+	// Keep the sum if it was lower than n, that is, if subtracting n borrowed
+	// and the addition did not carry out.
+	_, borrow = bits.Sub64(carry, 0, borrow)
+	for i := 0; i < 4; i++ {
+		s[i] = cmovznz(borrow, diff[i], sum[i])
+	}
+	return s
+}
+
+// Sub sets s = t1 - t2, and returns s.
+func (s *Scalar) Sub(t1, t2 *Scalar) *Scalar {
+	var diff [4]uint64
+	var borrow, carry uint64
+	for i := 0; i < 4; i++ {
+		diff[i], borrow = bits.Sub64(t1[i], t2[i], borrow)
+	}
+	mask := -borrow
+	for i := 0; i < 4; i++ {
+		s[i], carry = bits.Add64(diff[i], order[i]&mask, carry)
+	}
+	return s
+}
+
+// Negate sets s = -t, and returns s.
+func (s *Scalar) Negate(t *Scalar) *Scalar {
+	return s.Sub(new(Scalar), t)
+}
+
+// IsHigh returns 1 if s > (n - 1) / 2, and zero otherwise, in constant time.
+//
+// Exactly one of s and -s is high, unless s is zero. ECDSA signatures with a
+// high s value are non-standard under BIP 62 and BIP 146.
+func (s *Scalar) IsHigh() int {
+	var tmp Scalar
+	scalarMontMul(&tmp, s, &Scalar{1})
+	// s > halfOrder if and only if halfOrder - s borrows.
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		_, borrow = bits.Sub64(halfOrder[i], tmp[i], borrow)
+	}
+	return int(borrow)
+}
+
+// Canonicalize sets s = -s if s is high, so that s is always in the lower half
+// of the range [0, n), in constant time. See IsHigh.
+func (s *Scalar) Canonicalize() {
+	neg := new(Scalar).Negate(s)
+	s.Select(neg, s, s.IsHigh())
+}
+
+// Mul sets s = t1 * t2, and returns s.
+func (s *Scalar) Mul(t1, t2 *Scalar) *Scalar {
+	scalarMontMul(s, t1, t2)
+	return s
+}
+
+// Square sets s = t * t, and returns s.
+func (s *Scalar) Square(t *Scalar) *Scalar {
+	scalarMontMul(s, t, t)
+	return s
+}
+
+// Invert sets s = 1/t, and returns s.
+//
+// If t == 0, Invert returns s = 0.
+func (s *Scalar) Invert(t *Scalar) *Scalar {
+	// Inversion is implemented as exponentiation with exponent n - 2. The
+	// exponent is public, so branching on its bits is safe.
+	exp := order
+	exp[0] -= 2
+
+	z := new(Scalar).One()
+	for i := 3; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			z.Square(z)
+			if exp[i]>>j&1 == 1 {
+				z.Mul(z, t)
+			}
+		}
+	}
+	return s.Set(z)
+}
+
+// scalarMontMul sets out = x * y * 2⁻²⁵⁶ mod n, using the coarsely integrated
+// operand scanning (CIOS) method. x and y must be lower than n, and out may
+// overlap with either of them.
+func scalarMontMul(out, x, y *Scalar) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		var c, cc uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		// t = (t + m * n) / 2^64, where m is chosen so the division is exact.
+		m := t[0] * orderInv
+		hi, lo := bits.Mul64(m, order[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, order[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+
+	// t < 2n, so a single conditional subtraction fully reduces it.
+	var diff [4]uint64
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		diff[i], borrow = bits.Sub64(t[i], order[i], borrow)
+	}
+	_, borrow = bits.Sub64(t[4], 0, borrow)
+	for i := 0; i < 4; i++ {
+		out[i] = cmovznz(borrow, diff[i], t[i])
+	}
+}
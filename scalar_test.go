@@ -0,0 +1,356 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+)
+
+var bigOrder, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+func scalarFromBig(t testing.TB, v *big.Int) *Scalar {
+	s, err := new(Scalar).SetBytes(v.FillBytes(make([]byte, ElementLength)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func scalarToBig(s *Scalar) *big.Int {
+	return new(big.Int).SetBytes(s.Bytes())
+}
+
+func randomBigScalar(t testing.TB) *big.Int {
+	return new(big.Int).Mod(new(big.Int).SetBytes(randomScalar(t)), bigOrder)
+}
+
+func TestScalarSetBytes(t *testing.T) {
+	one := make([]byte, ElementLength)
+	one[ElementLength-1] = 1
+	for i, v := range [][]byte{make([]byte, ElementLength), one, orderMinusOne} {
+		s, err := new(Scalar).SetBytes(v)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if !bytes.Equal(s.Bytes(), v) {
+			t.Errorf("%d: got %x, want %x", i, s.Bytes(), v)
+		}
+	}
+	if s, _ := new(Scalar).SetBytes(one); s.Equal(new(Scalar).One()) != 1 {
+		t.Error("SetBytes(1) != One()")
+	}
+
+	n := bigOrder.FillBytes(make([]byte, ElementLength))
+	for i, v := range [][]byte{n, bytes.Repeat([]byte{0xff}, ElementLength), one[1:]} {
+		if _, err := new(Scalar).SetBytes(v); err == nil {
+			t.Errorf("%d: expected error for %x", i, v)
+		}
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	vals := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(bigOrder, big.NewInt(1)),
+	}
+	for i := 0; i < 8; i++ {
+		vals = append(vals, randomBigScalar(t))
+	}
+	for _, a := range vals {
+		for _, b := range vals {
+			x, y := scalarFromBig(t, a), scalarFromBig(t, b)
+
+			want := new(big.Int).Add(a, b)
+			if got := scalarToBig(new(Scalar).Add(x, y)); got.Cmp(want.Mod(want, bigOrder)) != 0 {
+				t.Errorf("%x + %x: got %x, want %x", a, b, got, want)
+			}
+			want = new(big.Int).Sub(a, b)
+			if got := scalarToBig(new(Scalar).Sub(x, y)); got.Cmp(want.Mod(want, bigOrder)) != 0 {
+				t.Errorf("%x - %x: got %x, want %x", a, b, got, want)
+			}
+			want = new(big.Int).Mul(a, b)
+			if got := scalarToBig(new(Scalar).Mul(x, y)); got.Cmp(want.Mod(want, bigOrder)) != 0 {
+				t.Errorf("%x * %x: got %x, want %x", a, b, got, want)
+			}
+		}
+
+		x := scalarFromBig(t, a)
+		want := new(big.Int).Neg(a)
+		if got := scalarToBig(new(Scalar).Negate(x)); got.Cmp(want.Mod(want, bigOrder)) != 0 {
+			t.Errorf("-%x: got %x, want %x", a, got, want)
+		}
+		want = new(big.Int).ModInverse(a, bigOrder)
+		if want == nil {
+			want = new(big.Int)
+		}
+		if got := scalarToBig(new(Scalar).Invert(x)); got.Cmp(want) != 0 {
+			t.Errorf("1/%x: got %x, want %x", a, got, want)
+		}
+	}
+}
+
+func TestScalarSetBytesReduce(t *testing.T) {
+	n := bigOrder.FillBytes(make([]byte, ElementLength))
+	nPlusOne := new(big.Int).Add(bigOrder, big.NewInt(1)).FillBytes(make([]byte, ElementLength))
+	inputs := [][]byte{
+		nil,
+		{1},
+		n,
+		nPlusOne,
+		append(make([]byte, 32), n...),
+		bytes.Repeat([]byte{0xff}, 32),
+		bytes.Repeat([]byte{0xff}, 33),
+		bytes.Repeat([]byte{0xff}, 64),
+	}
+	for _, l := range []int{1, 31, 32, 33, 48, 64, 100} {
+		inputs = append(inputs, randomBytes(t, l))
+	}
+	for _, v := range inputs {
+		want := new(big.Int).SetBytes(v)
+		want.Mod(want, bigOrder)
+		got := new(Scalar).SetBytesReduce(v)
+		if scalarToBig(got).Cmp(want) != 0 {
+			t.Errorf("SetBytesReduce(%x) = %x, want %x", v, got.Bytes(), want)
+		}
+		if *got != *scalarFromBig(t, want) {
+			t.Errorf("SetBytesReduce(%x) is not fully reduced", v)
+		}
+	}
+
+	// Known reductions.
+	if got := new(Scalar).SetBytesReduce(n); got.IsZero() != 1 {
+		t.Errorf("n mod n = %x, want 0", got.Bytes())
+	}
+	if got := new(Scalar).SetBytesReduce(nPlusOne); got.Equal(new(Scalar).One()) != 1 {
+		t.Errorf("n + 1 mod n = %x, want 1", got.Bytes())
+	}
+
+	// SetBytes still rejects what SetBytesReduce accepts.
+	if _, err := new(Scalar).SetBytes(n); err == nil {
+		t.Error("SetBytes accepted n")
+	}
+}
+
+func TestHashToScalar(t *testing.T) {
+	// bits2int as defined in RFC 6979, Section 2.3.2, followed by a reduction
+	// modulo n. The RFC's own examples in Appendix A.1 use a 163-bit order, so
+	// its definition is used as the reference instead.
+	bits2int := func(b []byte) *big.Int {
+		x := new(big.Int).SetBytes(b)
+		if blen := 8 * len(b); blen > bigOrder.BitLen() {
+			x.Rsh(x, uint(blen-bigOrder.BitLen()))
+		}
+		return x.Mod(x, bigOrder)
+	}
+
+	sample256 := sha256.Sum256([]byte("sample"))
+	sample512 := sha512.Sum512([]byte("sample"))
+	sample1 := sha1.Sum([]byte("sample"))
+	sample384 := sha512.Sum384([]byte("sample"))
+	inputs := [][]byte{
+		nil, {1}, sample1[:], sample256[:], sample384[:], sample512[:],
+		bytes.Repeat([]byte{0xff}, 32), bytes.Repeat([]byte{0xff}, 64),
+		orderMinusOne, bigOrder.Bytes(),
+	}
+	for i := 0; i < 32; i++ {
+		inputs = append(inputs, randomScalar(t))
+	}
+	for _, h := range inputs {
+		got := scalarToBig(HashToScalar(h))
+		if want := bits2int(h); got.Cmp(want) != 0 {
+			t.Errorf("HashToScalar(%x) = %x, want %x", h, got, want)
+		}
+	}
+
+	// For SHA-256 the digest is used as is, and for longer digests only the
+	// leftmost 32 bytes matter, which differs from SetBytesReduce.
+	if got := HashToScalar(sample256[:]).Bytes(); !bytes.Equal(got, sample256[:]) {
+		t.Errorf("HashToScalar(SHA-256(sample)) = %x, want %x", got, sample256)
+	}
+	if HashToScalar(sample512[:]).Equal(HashToScalar(sample512[:32])) != 1 {
+		t.Error("64-byte digest not truncated to its leftmost 32 bytes")
+	}
+	if HashToScalar(sample512[:]).Equal(new(Scalar).SetBytesReduce(sample512[:])) == 1 {
+		t.Error("64-byte digest reduced instead of truncated")
+	}
+}
+
+func randomBytes(t testing.TB, n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestScalarWipe(t *testing.T) {
+	s := new(Scalar).One()
+	s.Wipe()
+	for _, limb := range s {
+		if limb != 0 {
+			t.Fatalf("scalar not wiped: %x", *s)
+		}
+	}
+	if s.IsZero() != 1 {
+		t.Error("wiped scalar is not zero")
+	}
+}
+
+func TestHalfOrder(t *testing.T) {
+	half := new(big.Int).SetBytes(HalfOrder())
+	n := new(big.Int).Lsh(half, 1)
+	n.Add(n, big.NewInt(1))
+	if !bytes.Equal(n.Bytes(), Order()) {
+		t.Errorf("2 * HalfOrder() + 1 = %x, want %x", n, Order())
+	}
+	if len(HalfOrder()) != ElementLength {
+		t.Errorf("HalfOrder() is %d bytes", len(HalfOrder()))
+	}
+
+	// The threshold itself is low, and the next value is high.
+	h, err := new(Scalar).SetBytes(HalfOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.IsHigh() != 0 {
+		t.Error("HalfOrder() is high")
+	}
+	if h.Add(h, new(Scalar).One()); h.IsHigh() != 1 {
+		t.Error("HalfOrder() + 1 is not high")
+	}
+
+	// The result is a fresh copy.
+	HalfOrder()[0] ^= 0xff
+	if HalfOrder()[0] != 0x7f {
+		t.Error("modifying the result of HalfOrder changed it")
+	}
+}
+
+func TestScalarIsHigh(t *testing.T) {
+	half := new(big.Int).Rsh(bigOrder, 1)
+	inputs := []*big.Int{
+		big.NewInt(0), big.NewInt(1),
+		new(big.Int).Sub(half, big.NewInt(1)), half,
+		new(big.Int).Add(half, big.NewInt(1)), new(big.Int).Add(half, big.NewInt(2)),
+		new(big.Int).Sub(bigOrder, big.NewInt(1)),
+	}
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, randomBigScalar(t))
+	}
+	for _, v := range inputs {
+		s := scalarFromBig(t, v)
+		want := 0
+		if v.Cmp(half) > 0 {
+			want = 1
+		}
+		if got := s.IsHigh(); got != want {
+			t.Errorf("IsHigh(%x) = %d, want %d", v, got, want)
+		}
+
+		s.Canonicalize()
+		if s.IsHigh() != 0 {
+			t.Errorf("Canonicalize(%x) = %x is high", v, s.Bytes())
+		}
+		c := scalarToBig(s)
+		if c.Cmp(v) != 0 && new(big.Int).Add(c, v).Cmp(bigOrder) != 0 {
+			t.Errorf("Canonicalize(%x) = %x, want %x or its negation", v, c, v)
+		}
+		if want == 0 && c.Cmp(v) != 0 {
+			t.Errorf("Canonicalize changed low scalar %x to %x", v, c)
+		}
+	}
+}
+func TestOrderAndP(t *testing.T) {
+	// SEC 2, Version 2.0, Section 2.4.1, with the decimal values as a
+	// cross-check.
+	for _, tt := range []struct {
+		name string
+		f    func() []byte
+		hex  string
+		dec  string
+	}{
+		{"Order", Order,
+			"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			"115792089237316195423570985008687907852837564279074904382605163141518161494337"},
+		{"P", P,
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+			"115792089237316195423570985008687907853269984665640564039457584007908834671663"},
+	} {
+		got := tt.f()
+		if !bytes.Equal(got, decodeHex(tt.hex)) {
+			t.Errorf("%s() = %x, want %s", tt.name, got, tt.hex)
+		}
+		if want, _ := new(big.Int).SetString(tt.dec, 10); new(big.Int).SetBytes(got).Cmp(want) != 0 {
+			t.Errorf("%s() = %x, want %s in decimal", tt.name, got, tt.dec)
+		}
+		// Every call returns a fresh copy.
+		got[0] = 0
+		if again := tt.f(); again[0] != 0xff {
+			t.Errorf("%s() returned a shared slice", tt.name)
+		}
+	}
+	if new(big.Int).SetBytes(Order()).Cmp(bigOrder) != 0 {
+		t.Errorf("Order() disagrees with bigOrder")
+	}
+}
+
+func TestScalarSelect(t *testing.T) {
+	a := scalarFromBig(t, randomBigScalar(t))
+	b := scalarFromBig(t, randomBigScalar(t))
+	if got := new(Scalar).Select(a, b, 1); got.Equal(a) != 1 {
+		t.Error("Select(a, b, 1) != a")
+	}
+	if got := new(Scalar).Select(a, b, 0); got.Equal(b) != 1 {
+		t.Error("Select(a, b, 0) != b")
+	}
+	// The receiver may overlap with the operands.
+	if got := new(Scalar).Set(b); got.Select(a, got, 1).Equal(a) != 1 {
+		t.Error("aliased Select(a, b, 1) != a")
+	}
+}
+
+func TestScalarTable(t *testing.T) {
+	k := randomBigScalar(t)
+	table := newScalarTable(scalarFromBig(t, k))
+	s := new(Scalar)
+	for n := 0; n < 16; n++ {
+		table.Select(s, uint8(n))
+		want := new(big.Int).Mul(k, big.NewInt(int64(n)))
+		want.Mod(want, bigOrder)
+		if got := scalarToBig(s); got.Cmp(want) != 0 {
+			t.Errorf("Select(%d) = %x, want %x", n, got, want)
+		}
+	}
+
+	// Select must read every entry, whatever the index, so that its memory
+	// access pattern doesn't depend on it. A missing entry must make every
+	// selection fail, not just the one for its index.
+	for _, n := range []uint8{0, 1, 14, 15} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Select(%d) didn't read entry 7", n)
+				}
+			}()
+			broken := *table
+			broken[7] = nil
+			broken.Select(s, n)
+		}()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Select(16) didn't panic")
+		}
+	}()
+	table.Select(s, 16)
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schnorr implements BIP340 Schnorr signatures over secp256k1, as used
+// by Bitcoin's Taproot.
+//
+// Public keys are 32-byte x-only encodings of points with an even y
+// coordinate, and signatures are 64 bytes long.
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+// Sign produces a BIP340 signature of msg with the private key privkey, using
+// the 32 bytes of auxRand as auxiliary randomness. auxRand should be fresh
+// randomness, but signatures are still secure if it is predictable or zero.
+func Sign(privkey [32]byte, msg, auxRand []byte) ([64]byte, error) {
+	var sig [64]byte
+	if len(auxRand) != 32 {
+		return sig, errors.New("schnorr: auxiliary randomness must be 32 bytes")
+	}
+	d, err := new(secp256k1.Scalar).SetBytes(privkey[:])
+	if err != nil || d.IsZero() == 1 {
+		return sig, errors.New("schnorr: invalid private key")
+	}
+	P, err := secp256k1.NewPoint().ScalarBaseMult(privkey[:])
+	if err != nil {
+		return sig, err
+	}
+	Pc := P.BytesCompressed()
+	condNegate(d, int(Pc[0]&1))
+	pk := Pc[1:]
+
+	t := taggedHash("BIP0340/aux", auxRand)
+	for i, b := range d.Bytes() {
+		t[i] ^= b
+	}
+	k := new(secp256k1.Scalar).SetBytesReduce(taggedHash("BIP0340/nonce", t, pk, msg))
+	if k.IsZero() == 1 {
+		return sig, errors.New("schnorr: nonce is zero")
+	}
+	R, err := secp256k1.NewPoint().ScalarBaseMult(k.Bytes())
+	if err != nil {
+		return sig, err
+	}
+	Rc := R.BytesCompressed()
+	condNegate(k, int(Rc[0]&1))
+
+	e := new(secp256k1.Scalar).SetBytesReduce(taggedHash("BIP0340/challenge", Rc[1:], pk, msg))
+	s := e.Mul(e, d)
+	s.Add(s, k)
+	copy(sig[:32], Rc[1:])
+	copy(sig[32:], s.Bytes())
+
+	// Verify the signature to protect against fault attacks.
+	var pub [32]byte
+	copy(pub[:], pk)
+	if !Verify(pub, msg, sig) {
+		return [64]byte{}, errors.New("schnorr: produced signature does not verify")
+	}
+	return sig, nil
+}
+
+// Verify reports whether sig is a valid BIP340 signature of msg by the x-only
+// public key pubkey.
+func Verify(pubkey [32]byte, msg []byte, sig [64]byte) bool {
+	P, err := secp256k1.LiftX(pubkey[:])
+	if err != nil {
+		return false
+	}
+	// r must be lower than p.
+	if _, err := new(secp256k1.Element).SetBytes(sig[:32]); err != nil {
+		return false
+	}
+	s, err := new(secp256k1.Scalar).SetBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+	e := new(secp256k1.Scalar).SetBytesReduce(taggedHash("BIP0340/challenge", sig[:32], pubkey[:], msg))
+
+	// R = [s]G - [e]P
+	e.Negate(e)
+	R, err := secp256k1.NewPoint().ScalarDoubleBaseMult(s.Bytes(), P, e.Bytes())
+	if err != nil || R.IsInfinity() == 1 {
+		return false
+	}
+	Rc := R.BytesCompressed()
+	return Rc[0] == 2 && string(Rc[1:]) == string(sig[:32])
+}
+
+// BatchVerify reports whether every sigs[i] is a valid BIP340 signature of
+// msgs[i] by the x-only public key pubkeys[i]. It returns false if the slices
+// have different lengths, and true for an empty batch.
+//
+// It implements the batch verification algorithm of BIP340, which checks a
+// random linear combination of the verification equations with a single
+// multi-scalar multiplication. The speedup over calling Verify for each
+// signature grows with the size of the batch, to about a factor of two for a
+// hundred signatures. If it returns false, at least one signature is invalid,
+// but it doesn't report which one.
+//
+// BatchVerify is variable time, like Verify, and must only be used with public
+// inputs.
+func BatchVerify(pubkeys [][32]byte, msgs [][]byte, sigs [][64]byte) bool {
+	if len(pubkeys) != len(msgs) || len(pubkeys) != len(sigs) {
+		return false
+	}
+	u := len(sigs)
+	switch u {
+	case 0:
+		return true
+	case 1:
+		return Verify(pubkeys[0], msgs[0], sigs[0])
+	}
+
+	// Check (s₁ + a₂s₂ + ... + aᵤsᵤ)G = R₁ + a₂R₂ + ... + aᵤRᵤ +
+	// e₁P₁ + (a₂e₂)P₂ + ... + (aᵤeᵤ)Pᵤ as
+	// (-Σ aᵢsᵢ)G + Σ aᵢRᵢ + Σ (aᵢeᵢ)Pᵢ = 0, with a₁ = 1.
+	points := make([]*secp256k1.Point, 0, 2*u+1)
+	scalars := make([][]byte, 0, 2*u+1)
+	points = append(points, secp256k1.NewGenerator())
+	scalars = append(scalars, nil)
+	sum := new(secp256k1.Scalar)
+	a := new(secp256k1.Scalar).One()
+	for i := range sigs {
+		P, err := secp256k1.LiftX(pubkeys[i][:])
+		if err != nil {
+			return false
+		}
+		// lift_x rejects r values that are not lower than p.
+		R, err := secp256k1.LiftX(sigs[i][:32])
+		if err != nil {
+			return false
+		}
+		s, err := new(secp256k1.Scalar).SetBytes(sigs[i][32:])
+		if err != nil {
+			return false
+		}
+		e := new(secp256k1.Scalar).SetBytesReduce(taggedHash("BIP0340/challenge", sigs[i][:32], pubkeys[i][:], msgs[i]))
+
+		if i > 0 {
+			if a, err = randomNonZeroScalar(); err != nil {
+				return false
+			}
+		}
+		sum.Add(sum, s.Mul(s, a))
+		points = append(points, R, P)
+		scalars = append(scalars, a.Bytes(), e.Mul(e, a).Bytes())
+	}
+	scalars[0] = sum.Negate(sum).Bytes()
+
+	Q, err := secp256k1.MultiScalarMult(points, scalars)
+	return err == nil && Q.IsInfinity() == 1
+}
+
+// randomNonZeroScalar returns a uniformly random scalar in [1, n) read from
+// crypto/rand, for the coefficients of BatchVerify.
+func randomNonZeroScalar() (*secp256k1.Scalar, error) {
+	var buf [32]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		a, err := new(secp256k1.Scalar).SetBytes(buf[:])
+		if err == nil && a.IsZero() == 0 {
+			return a, nil
+		}
+	}
+}
+
+// taggedHash is TaggedHash returning a slice, for use with SetBytesReduce.
+func taggedHash(tag string, msgs ...[]byte) []byte {
+	h := TaggedHash(tag, msgs...)
+	return h[:]
+}
+
+// condNegate sets s = -s if cond is 1, and leaves it unchanged if cond is 0,
+// in constant time.
+func condNegate(s *secp256k1.Scalar, cond int) {
+	neg := new(secp256k1.Scalar).Negate(s)
+	s.Select(neg, s, cond)
+}
@@ -0,0 +1,238 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestVectors runs the BIP340 test vectors from testdata/bip340-vectors.csv,
+// in the format of the test-vectors.csv file of the BIP.
+func TestVectors(t *testing.T) {
+	f, err := os.Open("testdata/bip340-vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range records[1:] {
+		index, secKey, pubKey, auxRand, msg, sigHex, result, comment :=
+			rec[0], rec[1], rec[2], rec[3], rec[4], rec[5], rec[6], rec[7]
+		t.Run(index, func(t *testing.T) {
+			var pub [32]byte
+			copy(pub[:], decodeHex(t, pubKey))
+			var sig [64]byte
+			copy(sig[:], decodeHex(t, sigHex))
+			m := decodeHex(t, msg)
+
+			if secKey != "" {
+				var priv [32]byte
+				copy(priv[:], decodeHex(t, secKey))
+				got, err := Sign(priv, m, decodeHex(t, auxRand))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != sig {
+					t.Errorf("Sign: got %X, want %X", got, sig)
+				}
+			}
+
+			want := result == "TRUE"
+			if got := Verify(pub, m, sig); got != want {
+				t.Errorf("Verify: got %v, want %v (%s)", got, want, comment)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	var priv [32]byte
+	aux := make([]byte, 32)
+	for i := 0; i < 16; i++ {
+		rand.Read(priv[:])
+		rand.Read(aux)
+		msg := make([]byte, i*7)
+		rand.Read(msg)
+
+		sig, err := Sign(priv, msg, aux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		P, err := publicKey(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(P, msg, sig) {
+			t.Fatal("valid signature rejected")
+		}
+		msg = append(msg, 0)
+		if Verify(P, msg, sig) {
+			t.Error("signature verified for a different message")
+		}
+	}
+}
+
+func TestSignInvalid(t *testing.T) {
+	var priv [32]byte
+	if _, err := Sign(priv, nil, make([]byte, 32)); err == nil {
+		t.Error("expected error for zero private key")
+	}
+	priv[31] = 1
+	if _, err := Sign(priv, nil, make([]byte, 31)); err == nil {
+		t.Error("expected error for short auxiliary randomness")
+	}
+	copy(priv[:], bytes.Repeat([]byte{0xff}, 32))
+	if _, err := Sign(priv, nil, make([]byte, 32)); err == nil {
+		t.Error("expected error for private key larger than n")
+	}
+}
+
+func publicKey(priv [32]byte) ([32]byte, error) {
+	var pub [32]byte
+	P, err := secp256k1.NewPoint().ScalarBaseMult(priv[:])
+	if err != nil {
+		return pub, err
+	}
+	copy(pub[:], P.BytesCompressed()[1:])
+	return pub, nil
+}
+
+func batch(t testing.TB, n int) (pubkeys [][32]byte, msgs [][]byte, sigs [][64]byte) {
+	var priv [32]byte
+	aux := make([]byte, 32)
+	for i := 0; i < n; i++ {
+		rand.Read(priv[:])
+		rand.Read(aux)
+		msg := make([]byte, 32)
+		rand.Read(msg)
+		sig, err := Sign(priv, msg, aux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		P, err := publicKey(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubkeys = append(pubkeys, P)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+	return
+}
+
+func TestBatchVerify(t *testing.T) {
+	pubkeys, msgs, sigs := batch(t, 16)
+	if !BatchVerify(pubkeys, msgs, sigs) {
+		t.Fatal("valid batch rejected")
+	}
+	if !BatchVerify(pubkeys[:1], msgs[:1], sigs[:1]) {
+		t.Error("valid batch of one rejected")
+	}
+	if !BatchVerify(nil, nil, nil) {
+		t.Error("empty batch rejected")
+	}
+	if BatchVerify(pubkeys, msgs[:15], sigs) || BatchVerify(pubkeys, msgs, sigs[:15]) {
+		t.Error("batch with mismatched lengths accepted")
+	}
+
+	for _, i := range []int{0, 7, 15} {
+		tampered := append([][64]byte(nil), sigs...)
+		tampered[i][63] ^= 1
+		if BatchVerify(pubkeys, msgs, tampered) {
+			t.Errorf("batch with tampered s at %d accepted", i)
+		}
+		tampered = append([][64]byte(nil), sigs...)
+		tampered[i][0] ^= 1
+		if BatchVerify(pubkeys, msgs, tampered) {
+			t.Errorf("batch with tampered r at %d accepted", i)
+		}
+		swapped := append([][]byte(nil), msgs...)
+		swapped[i], swapped[(i+1)%16] = swapped[(i+1)%16], swapped[i]
+		if BatchVerify(pubkeys, swapped, sigs) {
+			t.Errorf("batch with swapped messages at %d accepted", i)
+		}
+	}
+
+	// Two invalid signatures whose errors cancel out would pass a plain sum
+	// of the verification equations, but not a random linear combination.
+	one := new(secp256k1.Scalar).One()
+	s0, _ := new(secp256k1.Scalar).SetBytes(sigs[0][32:])
+	s1, _ := new(secp256k1.Scalar).SetBytes(sigs[1][32:])
+	cancel := append([][64]byte(nil), sigs...)
+	copy(cancel[0][32:], s0.Add(s0, one).Bytes())
+	copy(cancel[1][32:], s1.Sub(s1, one).Bytes())
+	if BatchVerify(pubkeys, msgs, cancel) {
+		t.Error("batch with cancelling invalid signatures accepted")
+	}
+}
+
+func TestBatchVerifyVectors(t *testing.T) {
+	f, err := os.Open("testdata/bip340-vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkeys, msgs, sigs := batch(t, 4)
+	for _, rec := range records[1:] {
+		index, pubKey, msg, sigHex, result := rec[0], rec[2], rec[4], rec[5], rec[6]
+		var pub [32]byte
+		var sig [64]byte
+		if len(decodeHex(t, pubKey)) != 32 || len(decodeHex(t, sigHex)) != 64 {
+			continue
+		}
+		copy(pub[:], decodeHex(t, pubKey))
+		copy(sig[:], decodeHex(t, sigHex))
+		got := BatchVerify(append(pubkeys, pub), append(msgs, decodeHex(t, msg)), append(sigs, sig))
+		if want := result == "TRUE"; got != want {
+			t.Errorf("vector %s: BatchVerify = %v, want %v", index, got, want)
+		}
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	for _, n := range []int{1, 16, 128} {
+		pubkeys, msgs, sigs := batch(b, n)
+		b.Run(fmt.Sprintf("Verify/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range sigs {
+					if !Verify(pubkeys[j], msgs[j], sigs[j]) {
+						b.Fatal("valid signature rejected")
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("BatchVerify/n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if !BatchVerify(pubkeys, msgs, sigs) {
+					b.Fatal("valid batch rejected")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"hash"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCachedTags bounds the number of midstates kept by TaggedHash, so that
+// callers passing many distinct tags don't grow the cache without limit.
+const maxCachedTags = 64
+
+var (
+	midstates      sync.Map // tag string → marshaled SHA-256 state
+	midstatesCount int32
+)
+
+// TaggedHash returns the BIP340 tagged hash of the concatenation of msgs,
+//
+//	SHA256(SHA256(tag) ‖ SHA256(tag) ‖ msgs[0] ‖ msgs[1] ‖ ...),
+//
+// as used by BIP340 signatures and nonces, and by BIP341 Taproot tweaks.
+//
+// The SHA-256 state after the 64-byte prefix only depends on tag, so it is
+// computed once per tag and cached, saving two of the compression function
+// calls of every hash.
+func TaggedHash(tag string, msgs ...[]byte) [32]byte {
+	h := taggedHasher(tag)
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// taggedHasher returns a SHA-256 hash that already absorbed the tagged hash
+// prefix of tag.
+func taggedHasher(tag string) hash.Hash {
+	h := sha256.New()
+	if state, ok := midstates.Load(tag); ok {
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state.([]byte)); err != nil {
+			panic("schnorr: internal error: invalid SHA-256 midstate: " + err.Error())
+		}
+		return h
+	}
+
+	tagHash := sha256.Sum256([]byte(tag))
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	if atomic.LoadInt32(&midstatesCount) < maxCachedTags {
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			panic("schnorr: internal error: can't marshal SHA-256 state: " + err.Error())
+		}
+		if _, loaded := midstates.LoadOrStore(tag, state); !loaded {
+			atomic.AddInt32(&midstatesCount, 1)
+		}
+	}
+	return h
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestTaggedHash(t *testing.T) {
+	g := "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	tests := []struct {
+		tag  string
+		msgs []string
+		want string
+	}{
+		{"BIP0340/challenge", nil,
+			"c216d352f5818b7b4beacd4ae0a26fe888080823d2a598856661bcd54f1b3713"},
+		{"BIP0340/aux", []string{"0000000000000000000000000000000000000000000000000000000000000000"},
+			"54f169cfc9e2e5727480441f90ba25c488f461c70b5ea5dcaaf7af69270aa514"},
+		{"BIP0340/nonce", []string{"61", "6263"},
+			"000beb2c0c2b6cebe3a7e17e283816c2cd4a02e148ea65e9f218d8007d35de4b"},
+		{"TapTweak", []string{g},
+			"3cf5216d476a5e637bf0da674e50ddf55c403270dd36494dfcca438132fa30e7"},
+	}
+	for _, tt := range tests {
+		var msgs [][]byte
+		for _, m := range tt.msgs {
+			msgs = append(msgs, decodeHex(t, m))
+		}
+		// The second call uses the cached midstate.
+		for i := 0; i < 2; i++ {
+			got := TaggedHash(tt.tag, msgs...)
+			if hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("TaggedHash(%q, %v) = %x, want %s", tt.tag, tt.msgs, got, tt.want)
+			}
+		}
+	}
+}
+
+// TestTaggedHashCache checks that tags past the cache limit, and concurrent
+// callers, still get the right hash.
+func TestTaggedHashCache(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2*maxCachedTags; i++ {
+				tag := fmt.Sprintf("test/%d", i)
+				tagHash := sha256.Sum256([]byte(tag))
+				want := sha256.Sum256(append(append(tagHash[:], tagHash[:]...), "msg"...))
+				if got := TaggedHash(tag, []byte("m"), []byte("sg")); got != want {
+					t.Errorf("TaggedHash(%q) = %x, want %x", tag, got, want)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkTaggedHash(b *testing.B) {
+	msg := make([]byte, 32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		TaggedHash("BIP0340/challenge", msg, msg, msg)
+	}
+}
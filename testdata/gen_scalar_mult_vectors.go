@@ -0,0 +1,125 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// This program generates scalar-mult-vectors.csv, run from the repository root
+// with
+//
+//	go run testdata/gen_scalar_mult_vectors.go > testdata/scalar-mult-vectors.csv
+//
+// It doesn't use the secp256k1 package: the products are computed with a
+// textbook double-and-add over math/big affine coordinates. The bases other
+// than the generator and the pseudo-random scalars are derived from SHA-256,
+// so the output is deterministic.
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+var (
+	p, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	n, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+)
+
+// point is an affine point. The point at infinity is nil.
+type point struct{ x, y *big.Int }
+
+func add(a, b *point) *point {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	var l *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		if s := new(big.Int).Add(a.y, b.y); s.Mod(s, p).Sign() == 0 {
+			return nil
+		}
+		// λ = 3x² / 2y
+		num := new(big.Int).Mul(a.x, a.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(a.y, 1)
+		l = num.Mul(num, den.ModInverse(den, p))
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		den.Mod(den, p)
+		l = num.Mul(num, den.ModInverse(den, p))
+	}
+	l.Mod(l, p)
+	x := new(big.Int).Mul(l, l)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, p)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, l).Sub(y, a.y).Mod(y, p)
+	return &point{x, y}
+}
+
+func mul(q *point, k *big.Int) *point {
+	var r *point
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = add(r, r)
+		if k.Bit(i) == 1 {
+			r = add(r, q)
+		}
+	}
+	return r
+}
+
+func compressed(q *point) string {
+	if q == nil {
+		return "00"
+	}
+	return fmt.Sprintf("%02x%064x", 2+q.y.Bit(0), q.x)
+}
+
+// derived returns SHA-256(label || i) as an integer.
+func derived(label string, i int) *big.Int {
+	h := sha256.Sum256([]byte(fmt.Sprintf("secp256k1 scalar-mult-vectors %s %d", label, i)))
+	return new(big.Int).SetBytes(h[:])
+}
+
+func main() {
+	g := &point{gx, gy}
+	one := big.NewInt(1)
+	halfN := new(big.Int).Rsh(n, 1)
+	scalars := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(7), big.NewInt(20),
+		new(big.Int).Lsh(one, 128),
+		new(big.Int).Lsh(one, 255),
+		halfN,                        // (n - 1) / 2
+		new(big.Int).Add(halfN, one), // (n + 1) / 2
+		new(big.Int).Sub(n, big.NewInt(2)),
+		new(big.Int).Sub(n, one),
+		n,
+		new(big.Int).Add(n, one),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 256), one),
+	}
+	for i := 0; i < 8; i++ {
+		scalars = append(scalars, derived("scalar", i))
+	}
+
+	bases := []*point{nil} // the generator, encoded as an empty base
+	for i := 0; i < 3; i++ {
+		bases = append(bases, mul(g, derived("base", i)))
+	}
+
+	fmt.Println("base,scalar,result")
+	for _, b := range bases {
+		q, enc := b, compressed(b)
+		if b == nil {
+			q, enc = g, ""
+		}
+		for _, k := range scalars {
+			fmt.Printf("%s,%064x,%s\n", enc, k, compressed(mul(q, k)))
+		}
+	}
+}
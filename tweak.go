@@ -0,0 +1,90 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import "errors"
+
+// TweakAdd returns the 32-byte private key (priv + tweak) mod n, as used by
+// BIP32 child key derivation. priv must be in [1, n-1] and tweak in [0, n-1].
+// If the result is zero, TweakAdd returns an error.
+func TweakAdd(priv, tweak []byte) ([]byte, error) {
+	k, t, err := parseTweak(priv, tweak)
+	if err != nil {
+		return nil, err
+	}
+	k.Add(k, t)
+	if k.IsZero() == 1 {
+		return nil, errors.New("tweaked secp256k1 private key is zero")
+	}
+	return k.Bytes(), nil
+}
+
+// TweakMul returns the 32-byte private key (priv * tweak) mod n. priv and
+// tweak must be in [1, n-1].
+func TweakMul(priv, tweak []byte) ([]byte, error) {
+	k, t, err := parseTweak(priv, tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.IsZero() == 1 {
+		return nil, errors.New("invalid secp256k1 tweak")
+	}
+	return k.Mul(k, t).Bytes(), nil
+}
+
+// parseTweak decodes a private key in [1, n-1] and a tweak in [0, n-1].
+func parseTweak(priv, tweak []byte) (k, t *Scalar, err error) {
+	if err := checkPrivateKey(priv); err != nil {
+		return nil, nil, err
+	}
+	k, _ = new(Scalar).SetBytes(priv)
+	t, err = new(Scalar).SetBytes(tweak)
+	if err != nil {
+		return nil, nil, errors.New("invalid secp256k1 tweak")
+	}
+	return k, t, nil
+}
+
+// TweakAdd sets p = p + [tweak]G, and returns p. It derives the public key of
+// TweakAdd(priv, tweak) from the public key p of priv.
+//
+// tweak must be a 32-byte big-endian scalar in [0, n-1]. If it is not, or if
+// the result is the point at infinity, TweakAdd returns nil and an error, and
+// p is unchanged.
+func (p *Point) TweakAdd(tweak []byte) (*Point, error) {
+	if len(tweak) != ElementLength || lessThanOrder(tweak) != 1 {
+		return nil, errors.New("invalid secp256k1 tweak")
+	}
+	q, err := NewPoint().ScalarBaseMult(tweak)
+	if err != nil {
+		return nil, err
+	}
+	q.Add(q, p)
+	if q.IsInfinity() == 1 {
+		return nil, errors.New("tweaked secp256k1 public key is the point at infinity")
+	}
+	return p.Set(q), nil
+}
+
+// TweakMul sets p = [tweak]p, and returns p. It derives the public key of
+// TweakMul(priv, tweak) from the public key p of priv.
+//
+// tweak must be a 32-byte big-endian scalar in [1, n-1]. If it is not, or if
+// p is the point at infinity, TweakMul returns nil and an error, and p is
+// unchanged.
+func (p *Point) TweakMul(tweak []byte) (*Point, error) {
+	// A zero tweak is rejected here, rather than as a product at infinity.
+	if !ValidPrivateKey(tweak) {
+		return nil, errors.New("invalid secp256k1 tweak")
+	}
+	q, err := NewPoint().ScalarMult(p, tweak)
+	if err != nil {
+		return nil, err
+	}
+	if q.IsInfinity() == 1 {
+		return nil, errors.New("tweaked secp256k1 public key is the point at infinity")
+	}
+	return p.Set(q), nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTweak(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		priv, tweak := randomScalar(t), randomScalar(t)
+		pub, err := NewPoint().ScalarBaseMult(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		added, err := TweakAdd(priv, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := NewPoint().ScalarBaseMult(added)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewPoint().Set(pub).TweakAdd(tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(want) != 1 {
+			t.Errorf("pub(TweakAdd(priv, t)) != pub(priv).TweakAdd(t)")
+		}
+
+		multiplied, err := TweakMul(priv, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err = NewPoint().ScalarBaseMult(multiplied)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err = NewPoint().Set(pub).TweakMul(tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(want) != 1 {
+			t.Errorf("pub(TweakMul(priv, t)) != pub(priv).TweakMul(t)")
+		}
+	}
+}
+
+func TestTweakInvalid(t *testing.T) {
+	one := make([]byte, ElementLength)
+	one[ElementLength-1] = 1
+	zero := make([]byte, ElementLength)
+	n := decodeHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+
+	// 1 + (n - 1) = 0
+	if _, err := TweakAdd(one, orderMinusOne); err == nil {
+		t.Error("TweakAdd to zero succeeded")
+	}
+	if _, err := NewGenerator().TweakAdd(orderMinusOne); err == nil {
+		t.Error("Point.TweakAdd to infinity succeeded")
+	}
+	if k, err := TweakAdd(one, zero); err != nil || !bytes.Equal(k, one) {
+		t.Errorf("TweakAdd(1, 0) = %x, %v", k, err)
+	}
+
+	for _, tweak := range [][]byte{n, one[1:]} {
+		if _, err := TweakAdd(one, tweak); err == nil {
+			t.Errorf("TweakAdd with tweak %x succeeded", tweak)
+		}
+		if _, err := TweakMul(one, tweak); err == nil {
+			t.Errorf("TweakMul with tweak %x succeeded", tweak)
+		}
+		p := NewGenerator()
+		if _, err := p.TweakAdd(tweak); err == nil {
+			t.Errorf("Point.TweakAdd with tweak %x succeeded", tweak)
+		}
+		if _, err := p.TweakMul(tweak); err == nil {
+			t.Errorf("Point.TweakMul with tweak %x succeeded", tweak)
+		}
+		if p.Equal(NewGenerator()) != 1 {
+			t.Error("failed tweak modified the receiver")
+		}
+	}
+	if _, err := TweakMul(one, zero); err == nil {
+		t.Error("TweakMul by zero succeeded")
+	}
+	if _, err := NewGenerator().TweakMul(zero); err == nil || err.Error() != "invalid secp256k1 tweak" {
+		t.Errorf("Point.TweakMul by zero: got %v, want an invalid tweak error", err)
+	}
+	if _, err := TweakAdd(zero, one); err == nil {
+		t.Error("TweakAdd of the zero private key succeeded")
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+)
+
+// This file implements variable-time operations, which are faster than their
+// constant-time counterparts but leak their inputs through timing. They must
+// only be used with public inputs, such as when verifying signatures.
+
+// ScalarMultVartime sets p = scalar * q, and returns p.
+//
+// ScalarMultVartime is NOT constant time: its running time, and its memory
+// access pattern, depend on the value of scalar. It must never be used with
+// secret scalars, such as private keys or nonces.
+func (p *Point) ScalarMultVartime(q *Point, scalar []byte) (*Point, error) {
+	const w = 5
+	table := oddMultiples(q, w)
+	naf := wnaf(scalar, w)
+
+	r := NewPoint()
+	started := false
+	for i := len(naf) - 1; i >= 0; i-- {
+		if started {
+			r.Double(r)
+		}
+		if d := naf[i]; d != 0 {
+			r.addDigit(table, d)
+			started = true
+		}
+	}
+	return p.Set(r), nil
+}
+
+// generatorWindow is the wNAF width used for the generator, whose odd
+// multiples are precomputed once and reused across calls.
+const generatorWindow = 8
+
+var generatorOddMultiples []*Point
+var generatorOddMultiplesOnce sync.Once
+
+// ScalarDoubleBaseMult sets p = aScalar * G + bScalar * q, where G is the
+// canonical generator, and returns p. This is the operation at the core of
+// ECDSA and Schnorr signature verification.
+//
+// The two multiplications are interleaved (Shamir's trick), so they share a
+// single chain of doublings, using a large precomputed wNAF table for G and a
+// small one computed on the fly for q.
+//
+// ScalarDoubleBaseMult is NOT constant time, and must never be used with
+// secret scalars.
+func (p *Point) ScalarDoubleBaseMult(aScalar []byte, q *Point, bScalar []byte) (*Point, error) {
+	generatorOddMultiplesOnce.Do(func() {
+		generatorOddMultiples = oddMultiples(NewGenerator(), generatorWindow)
+	})
+	const w = 5
+	qTable := oddMultiples(q, w)
+	aNAF := wnaf(aScalar, generatorWindow)
+	bNAF := wnaf(bScalar, w)
+
+	n := len(aNAF)
+	if len(bNAF) > n {
+		n = len(bNAF)
+	}
+	r := NewPoint()
+	started := false
+	for i := n - 1; i >= 0; i-- {
+		if started {
+			r.Double(r)
+		}
+		if i < len(aNAF) && aNAF[i] != 0 {
+			r.addDigit(generatorOddMultiples, aNAF[i])
+			started = true
+		}
+		if i < len(bNAF) && bNAF[i] != 0 {
+			r.addDigit(qTable, bNAF[i])
+			started = true
+		}
+	}
+	return p.Set(r), nil
+}
+
+// AddVartime sets p = p1 + p2, and returns p. The points may overlap.
+//
+// Unlike Add, it returns early when either operand is the point at infinity,
+// and uses the cheaper Double when the operands are equal, so it is faster
+// when summing points that are often the identity or repeated.
+//
+// AddVartime is NOT constant time, and must never be used with secret points.
+func (p *Point) AddVartime(p1, p2 *Point) *Point {
+	switch {
+	case p1.IsInfinity() == 1:
+		return p.Set(p2)
+	case p2.IsInfinity() == 1:
+		return p.Set(p1)
+	case p1.Equal(p2) == 1:
+		return p.Double(p1)
+	}
+	return p.Add(p1, p2)
+}
+
+// Accumulator sums a stream of points, one at a time, without collecting them
+// into a slice first. The zero value is ready to use, and sums to the point at
+// infinity.
+//
+// Additions are not batched: each Add performs one projective addition right
+// away. The sum stays in projective coordinates, so there is no inversion whose
+// cost batching could amortize, and buffering the points would only cost
+// memory.
+//
+// Accumulator uses AddVartime, so it is NOT constant time, and must never be
+// used with secret points.
+type Accumulator struct {
+	sum *Point
+}
+
+// Add adds p to the sum. p is not retained, and may be modified or reused
+// after Add returns.
+func (a *Accumulator) Add(p *Point) {
+	if p.IsInfinity() == 1 {
+		return
+	}
+	if a.sum == nil {
+		a.sum = NewPoint().Set(p)
+		return
+	}
+	a.sum.AddVartime(a.sum, p)
+}
+
+// Sum returns a new Point set to the sum of the points added so far. The
+// Accumulator can keep being used after Sum.
+func (a *Accumulator) Sum() *Point {
+	if a.sum == nil {
+		return NewPoint()
+	}
+	return NewPoint().Set(a.sum)
+}
+
+// Reset sets the sum back to the point at infinity.
+func (a *Accumulator) Reset() {
+	a.sum = nil
+}
+
+// MultiScalarMult returns the sum of scalars[i] * points[i]. The scalars are
+// big-endian and may have different lengths. If there are no points, the
+// result is the point at infinity.
+//
+// MultiScalarMult uses the bucket method (Pippenger's algorithm), with a window
+// size chosen from the number of points, which makes it much faster than
+// separate multiplications for large inputs.
+//
+// MultiScalarMult is NOT constant time, and must never be used with secret
+// scalars.
+func MultiScalarMult(points []*Point, scalars [][]byte) (*Point, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("mismatched number of points and scalars")
+	}
+	maxLen := 0
+	for _, s := range scalars {
+		if len(s) > maxLen {
+			maxLen = len(s)
+		}
+	}
+
+	c := pippengerWindow(len(points))
+	windows := (8*maxLen + c - 1) / c
+	buckets := make([]*Point, 1<<c-1)
+	result := NewPoint()
+	running, sum := NewPoint(), NewPoint()
+	for w := windows - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			result.Double(result)
+		}
+
+		// Sort the points into buckets by the value of their window digit.
+		for j := range buckets {
+			buckets[j] = nil
+		}
+		for i, s := range scalars {
+			d := scalarWindow(s, w*c, c)
+			if d == 0 {
+				continue
+			}
+			if buckets[d-1] == nil {
+				buckets[d-1] = NewPoint().Set(points[i])
+			} else {
+				buckets[d-1].Add(buckets[d-1], points[i])
+			}
+		}
+
+		// Σ j·bucket[j] is computed with a running sum, adding bucket[j]
+		// to the result j times with just two additions per bucket.
+		running.Set(NewPoint())
+		sum.Set(NewPoint())
+		for j := len(buckets) - 1; j >= 0; j-- {
+			if buckets[j] != nil {
+				running.Add(running, buckets[j])
+			}
+			sum.Add(sum, running)
+		}
+		result.Add(result, sum)
+	}
+	return result, nil
+}
+
+// pippengerWindow returns the bucket window size for n points, balancing the
+// n additions per window against the 2^(c+1) additions of the bucket sum.
+func pippengerWindow(n int) int {
+	c := bits.Len(uint(n)) - 3
+	if c < 2 {
+		c = 2
+	}
+	if c > 16 {
+		c = 16
+	}
+	return c
+}
+
+// scalarWindow returns bits [offset, offset+c) of the big-endian scalar s,
+// where bit zero is the least significant one.
+func scalarWindow(s []byte, offset, c int) int {
+	d := 0
+	for i := c - 1; i >= 0; i-- {
+		d <<= 1
+		if b := offset + i; b < 8*len(s) {
+			d |= int(s[len(s)-1-b/8]>>(b%8)) & 1
+		}
+	}
+	return d
+}
+
+// oddMultiples returns a table of the 2^(w-2) odd multiples [1]q, [3]q, ...,
+// [2^(w-1) - 1]q, for use with the digits returned by wnaf.
+func oddMultiples(q *Point, w uint) []*Point {
+	table := make([]*Point, 1<<(w-2))
+	table[0] = NewPoint().Set(q)
+	q2 := NewPoint().Double(q)
+	for i := 1; i < len(table); i++ {
+		table[i] = NewPoint().Add(table[i-1], q2)
+	}
+	return table
+}
+
+// addDigit sets p = p + [d]q, where table holds the odd multiples of q and d is
+// a non-zero wNAF digit.
+func (p *Point) addDigit(table []*Point, d int8) {
+	if d > 0 {
+		p.Add(p, table[d/2])
+	} else {
+		p.Sub(p, table[-d/2])
+	}
+}
+
+// wnaf returns the width-w non-adjacent form of the big-endian scalar, as a
+// little-endian sequence of digits. Every digit is either zero or odd and in
+// (-2^(w-1), 2^(w-1)), and any w consecutive digits have at most one non-zero
+// digit. w must be between 2 and 8.
+func wnaf(scalar []byte, w uint) []int8 {
+	bit := func(i int) int {
+		if i >= 8*len(scalar) {
+			return 0
+		}
+		return int(scalar[len(scalar)-1-i/8]>>(i%8)) & 1
+	}
+
+	// The final carry can extend the representation by one digit.
+	naf := make([]int8, 8*len(scalar)+1)
+	carry := 0
+	for i := 0; i < len(naf); {
+		if bit(i) == carry {
+			i++
+			continue
+		}
+
+		// The window value is odd, since bit(i) != carry. If it's at least
+		// 2^(w-1), use the negative digit and carry into the next window.
+		word := carry
+		for j := 0; j < int(w); j++ {
+			word += bit(i+j) << j
+		}
+		carry = word >> (w - 1) & 1
+		word -= carry << w
+		naf[i] = int8(word)
+		i += int(w)
+	}
+	return naf
+}
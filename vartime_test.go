@@ -0,0 +1,331 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// testScalars returns a set of edge case and random scalars of various lengths.
+func testScalars(t testing.TB) [][]byte {
+	scalars := [][]byte{
+		nil,
+		{0},
+		{1},
+		{0x80},
+		make([]byte, ElementLength),
+		orderMinusOne,
+		bigOrder.FillBytes(make([]byte, ElementLength)),
+		bytes.Repeat([]byte{0xff}, ElementLength),
+		bytes.Repeat([]byte{0xaa}, ElementLength),
+		bytes.Repeat([]byte{0x55}, 40),
+	}
+	for i := 0; i < 8; i++ {
+		scalars = append(scalars, randomScalar(t))
+	}
+	return scalars
+}
+
+func TestWNAF(t *testing.T) {
+	for _, w := range []uint{2, 3, 4, 5, 6, 8} {
+		for _, k := range testScalars(t) {
+			naf := wnaf(k, w)
+			sum := new(big.Int)
+			lastNonZero := -int(w)
+			for i := len(naf) - 1; i >= 0; i-- {
+				sum.Lsh(sum, 1)
+				sum.Add(sum, big.NewInt(int64(naf[i])))
+			}
+			for i, digit := range naf {
+				if digit == 0 {
+					continue
+				}
+				if d := int(digit); d%2 == 0 || d >= 1<<(w-1) || d <= -1<<(w-1) {
+					t.Errorf("w=%d, k=%x: invalid digit %d", w, k, digit)
+				}
+				if i-lastNonZero < int(w) {
+					t.Errorf("w=%d, k=%x: non-zero digits at %d and %d", w, k, lastNonZero, i)
+				}
+				lastNonZero = i
+			}
+			if want := new(big.Int).SetBytes(k); sum.Cmp(want) != 0 {
+				t.Errorf("w=%d: wNAF of %x evaluates to %x", w, k, sum)
+			}
+		}
+	}
+}
+
+func TestScalarMultVartime(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []*Point{NewGenerator(), q, NewPoint()} {
+		for _, k := range testScalars(t) {
+			want, err := NewPoint().ScalarMult(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewPoint().ScalarMultVartime(base, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Equal(want) != 1 {
+				t.Errorf("k=%x: got %x, want %x", k, got.Bytes(), want.Bytes())
+			}
+		}
+	}
+
+	// The receiver may overlap with the point.
+	k := randomScalar(t)
+	want, _ := NewPoint().ScalarMult(q, k)
+	if got, _ := NewPoint().Set(q).ScalarMultVartime(q, k); got.Equal(want) != 1 {
+		t.Errorf("aliased result differs")
+	}
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	k := randomScalar(b)
+	p := NewPoint()
+	b.Run("ScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(q, k)
+		}
+	})
+	b.Run("ScalarMultVartime", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.ScalarMultVartime(q, k)
+		}
+	})
+}
+
+func TestScalarDoubleBaseMult(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scalars := testScalars(t)
+	for i, a := range scalars {
+		b := scalars[len(scalars)-1-i]
+		for _, base := range []*Point{q, NewGenerator(), NewPoint()} {
+			want, err := NewPoint().ScalarMult(NewGenerator(), a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bq, err := NewPoint().ScalarMult(base, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want.Add(want, bq)
+
+			got, err := NewPoint().ScalarDoubleBaseMult(a, base, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Equal(want) != 1 {
+				t.Errorf("a=%x, b=%x: got %x, want %x", a, b, got.Bytes(), want.Bytes())
+			}
+		}
+	}
+}
+
+func BenchmarkScalarDoubleBaseMult(b *testing.B) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	k1, k2 := randomScalar(b), randomScalar(b)
+	p := NewPoint()
+	b.Run("Separate", func(b *testing.B) {
+		t := NewPoint()
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMult(k1)
+			t.ScalarMult(q, k2)
+			p.Add(p, t)
+		}
+	})
+	b.Run("ScalarDoubleBaseMult", func(b *testing.B) {
+		p.ScalarDoubleBaseMult(k1, q, k2)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.ScalarDoubleBaseMult(k1, q, k2)
+		}
+	})
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 40, 150} {
+		points := make([]*Point, n)
+		scalars := make([][]byte, n)
+		want := NewPoint()
+		edge := testScalars(t)
+		for i := range points {
+			var err error
+			points[i], err = NewPoint().ScalarBaseMult(randomScalar(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			scalars[i] = edge[i%len(edge)]
+			if i%7 == 3 {
+				points[i] = NewPoint()
+			}
+			sp, err := NewPoint().ScalarMult(points[i], scalars[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			want.Add(want, sp)
+		}
+		got, err := MultiScalarMult(points, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(want) != 1 {
+			t.Errorf("n=%d: got %x, want %x", n, got.Bytes(), want.Bytes())
+		}
+	}
+
+	if _, err := MultiScalarMult([]*Point{NewGenerator()}, nil); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	const n = 1000
+	points := make([]*Point, n)
+	scalars := make([][]byte, n)
+	for i := range points {
+		points[i], _ = NewPoint().ScalarBaseMult(randomScalar(b))
+		scalars[i] = randomScalar(b)
+	}
+	b.Run("Loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum, t := NewPoint(), NewPoint()
+			for j := range points {
+				t.ScalarMultVartime(points[j], scalars[j])
+				sum.Add(sum, t)
+			}
+		}
+	})
+	b.Run("MultiScalarMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MultiScalarMult(points, scalars)
+		}
+	})
+}
+
+func TestAddVartime(t *testing.T) {
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := []*Point{NewPoint(), NewGenerator(), NewPoint().Negate(NewGenerator()), q, NewPoint().Double(q)}
+	for _, p1 := range points {
+		for _, p2 := range points {
+			want := NewPoint().Add(p1, p2)
+			if got := NewPoint().AddVartime(p1, p2); got.Equal(want) != 1 {
+				t.Errorf("%v + %v: got %v, want %v", p1, p2, got, want)
+			}
+			// The receiver may overlap with either operand.
+			if got := NewPoint().Set(p1); got.AddVartime(got, p2).Equal(want) != 1 {
+				t.Errorf("%v + %v: aliased p1: got %v, want %v", p1, p2, got, want)
+			}
+			if got := NewPoint().Set(p2); got.AddVartime(p1, got).Equal(want) != 1 {
+				t.Errorf("%v + %v: aliased p2: got %v, want %v", p1, p2, got, want)
+			}
+		}
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	var acc Accumulator
+	if acc.Sum().IsInfinity() != 1 {
+		t.Error("the zero Accumulator doesn't sum to the point at infinity")
+	}
+
+	q, err := NewPoint().ScalarBaseMult(randomScalar(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Include the point at infinity, repeated points, and cancelling pairs.
+	points := []*Point{NewPoint(), q, q, NewGenerator(), NewPoint().Negate(q), NewPoint()}
+	for i := 0; i < 32; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		points = append(points, p)
+	}
+
+	want := NewPoint()
+	reused := NewPoint()
+	for i, p := range points {
+		// Add must not retain its argument.
+		reused.Set(p)
+		acc.Add(reused)
+		reused.Set(NewGenerator())
+
+		want.Add(want, p)
+		if got := acc.Sum(); got.Equal(want) != 1 {
+			t.Fatalf("after %d points: got %v, want %v", i+1, got, want)
+		}
+	}
+
+	// Sum returns a copy.
+	acc.Sum().Set(NewGenerator())
+	if acc.Sum().Equal(want) != 1 {
+		t.Error("modifying the result of Sum changed the Accumulator")
+	}
+
+	acc.Reset()
+	if acc.Sum().IsInfinity() != 1 || acc != (Accumulator{}) {
+		t.Error("Reset didn't return the Accumulator to its zero value")
+	}
+	acc.Add(q)
+	acc.Add(NewPoint().Negate(q))
+	if acc.Sum().IsInfinity() != 1 {
+		t.Error("q + -q is not the point at infinity")
+	}
+}
+
+func BenchmarkAddVartime(b *testing.B) {
+	// A sum of many points, a quarter of which are the point at infinity, as
+	// when accumulating sparse public inputs.
+	const n = 256
+	points := make([]*Point, n)
+	for i := range points {
+		if i%4 == 0 {
+			points[i] = NewPoint()
+			continue
+		}
+		p, err := NewPoint().ScalarBaseMult(randomScalar(b))
+		if err != nil {
+			b.Fatal(err)
+		}
+		points[i] = p
+	}
+	sum := NewPoint()
+	b.Run("Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum.Set(NewPoint())
+			for _, p := range points {
+				sum.Add(sum, p)
+			}
+		}
+	})
+	b.Run("AddVartime", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum.Set(NewPoint())
+			for _, p := range points {
+				sum.AddVartime(sum, p)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/big"
+	"os"
+	"testing"
+)
+
+// TestScalarMultVectors runs the vectors in testdata/scalar-mult-vectors.csv.
+// Each record is a compressed base point (empty for the generator), a 32-byte
+// big-endian scalar, and the compressed encoding of the product, "00" being
+// the point at infinity. The scalars include 0, n-1, n, n+1 and 2²⁵⁶-1.
+//
+// The file is produced by testdata/gen_scalar_mult_vectors.go, which computes
+// the products with math/big and none of this package's code. Every row was
+// also checked against OpenSSL's secp256k1 implementation when it was generated.
+func TestScalarMultVectors(t *testing.T) {
+	f, err := os.Open("testdata/scalar-mult-vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, rec := range records[1:] {
+		base, scalar, want := rec[0], decodeHex(rec[1]), decodeHex(rec[2])
+		if len(scalar) != ElementLength {
+			t.Fatalf("record %d: scalar is %d bytes", i, len(scalar))
+		}
+
+		q := NewGenerator()
+		if base == "" {
+			p, err := NewPoint().ScalarBaseMult(scalar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.BytesCompressed(); !bytes.Equal(got, want) {
+				t.Errorf("record %d: ScalarBaseMult(%x) = %x, want %x", i, scalar, got, want)
+			}
+		} else if _, err := q.SetBytes(decodeHex(base)); err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+
+		p, err := NewPoint().ScalarMult(q, scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.BytesCompressed(); !bytes.Equal(got, want) {
+			t.Errorf("record %d: ScalarMult(%x, %x) = %x, want %x", i, q.BytesCompressed(), scalar, got, want)
+		}
+	}
+}
+
+// bigPoint is an affine point used by the math/big reference implementation
+// below. The point at infinity is nil. The generic CurveParams of
+// crypto/elliptic can't be used as a reference, as it assumes a = -3.
+type bigPoint struct{ x, y *big.Int }
+
+var bigP, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+
+// bigAdd returns a + b with the textbook affine formulas.
+func bigAdd(a, b *bigPoint) *bigPoint {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	l := new(big.Int)
+	if a.x.Cmp(b.x) == 0 {
+		if s := new(big.Int).Add(a.y, b.y); s.Mod(s, bigP).Sign() == 0 {
+			return nil
+		}
+		// λ = 3x² / 2y
+		l.Mul(a.x, a.x).Mul(l, big.NewInt(3))
+		d := new(big.Int).Lsh(a.y, 1)
+		l.Mul(l, d.ModInverse(d, bigP))
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		l.Sub(b.y, a.y)
+		d := new(big.Int).Sub(b.x, a.x)
+		d.Mod(d, bigP)
+		l.Mul(l, d.ModInverse(d, bigP))
+	}
+	l.Mod(l, bigP)
+	x := new(big.Int).Mul(l, l)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, bigP)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, l).Sub(y, a.y).Mod(y, bigP)
+	return &bigPoint{x, y}
+}
+
+func toBigPoint(p *Point) *bigPoint {
+	b := p.Bytes()
+	if len(b) == 1 {
+		return nil
+	}
+	return &bigPoint{new(big.Int).SetBytes(b[1:33]), new(big.Int).SetBytes(b[33:])}
+}
+
+func checkBigPoint(t *testing.T, op string, got *Point, want *bigPoint) {
+	t.Helper()
+	wantBytes := []byte{0}
+	if want != nil {
+		wantBytes = make([]byte, 1+2*ElementLength)
+		wantBytes[0] = 4
+		want.x.FillBytes(wantBytes[1:33])
+		want.y.FillBytes(wantBytes[33:])
+	}
+	if gotBytes := got.Bytes(); !bytes.Equal(gotBytes, wantBytes) {
+		t.Errorf("%s = %x, want %x", op, gotBytes, wantBytes)
+	}
+}
+
+// TestAddDoubleReference cross-checks Add and Double, including the
+// exceptional cases of the affine formulas, against a math/big reference.
+func TestAddDoubleReference(t *testing.T) {
+	points := []*Point{NewPoint(), NewGenerator(), NewPoint().Negate(NewGenerator())}
+	for i := 0; i < 8; i++ {
+		p, err := NewPoint().ScalarBaseMult(randomScalar(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		points = append(points, p, NewPoint().Negate(p))
+	}
+
+	for _, p1 := range points {
+		b1 := toBigPoint(p1)
+		checkBigPoint(t, "Double", NewPoint().Double(p1), bigAdd(b1, b1))
+		for _, p2 := range points {
+			b2 := toBigPoint(p2)
+			checkBigPoint(t, "Add", NewPoint().Add(p1, p2), bigAdd(b1, b2))
+			checkBigPoint(t, "Add (scaled)", NewPoint().Add(scaled(t, p1), p2), bigAdd(b1, b2))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package wif implements Bitcoin's Wallet Import Format for secp256k1 private
+// keys: the base58check encoding of a network version byte, the 32-byte
+// scalar, and an optional 0x01 suffix marking a compressed public key.
+package wif
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wdvxdr1123/secp256k1"
+	"github.com/wdvxdr1123/secp256k1/internal/wifcodec"
+)
+
+var errInvalidPrivateKey = errors.New("wif: invalid private key")
+
+// EncodeWIF returns the WIF encoding of the 32-byte big-endian private key
+// priv, which must be in [1, n-1]. compressed marks the key as corresponding
+// to a compressed public key, and mainnet selects the mainnet (0x80) rather
+// than the testnet (0xef) version byte.
+func EncodeWIF(priv []byte, compressed bool, mainnet bool) (string, error) {
+	if !secp256k1.ValidPrivateKey(priv) {
+		return "", errInvalidPrivateKey
+	}
+	return wifcodec.Encode(priv, compressed, mainnet), nil
+}
+
+// DecodeWIF decodes a WIF private key, checking its checksum, and returns the
+// 32-byte big-endian scalar, which must be in [1, n-1], along with whether it
+// is marked as compressed and whether it uses the mainnet version byte.
+func DecodeWIF(s string) (priv []byte, compressed bool, mainnet bool, err error) {
+	priv, compressed, mainnet, err = wifcodec.Decode(s)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("wif: %w", err)
+	}
+	if !secp256k1.ValidPrivateKey(priv) {
+		return nil, false, false, errInvalidPrivateKey
+	}
+	return priv, compressed, mainnet, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 The secp256k1 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wif
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/wdvxdr1123/secp256k1/internal/base58"
+	"github.com/wdvxdr1123/secp256k1/internal/wifcodec"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestWIF(t *testing.T) {
+	tests := []struct {
+		wif                 string
+		key                 string
+		compressed, mainnet bool
+	}{
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", false, true},
+		{"KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", true, true},
+		{"5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", "0000000000000000000000000000000000000000000000000000000000000001", false, true},
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", "0000000000000000000000000000000000000000000000000000000000000001", true, true},
+		{"91gGn1HgSap6CbU12F6z3pJri26xzp7Ay1VW6NHCoEayNXwRpu2", "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d", false, false},
+		{"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", "0000000000000000000000000000000000000000000000000000000000000001", true, false},
+	}
+	for _, tt := range tests {
+		key := decodeHex(t, tt.key)
+		got, err := EncodeWIF(key, tt.compressed, tt.mainnet)
+		if err != nil {
+			t.Errorf("EncodeWIF(%s, %v, %v): %v", tt.key, tt.compressed, tt.mainnet, err)
+		} else if got != tt.wif {
+			t.Errorf("EncodeWIF(%s, %v, %v) = %s, want %s", tt.key, tt.compressed, tt.mainnet, got, tt.wif)
+		}
+
+		priv, compressed, mainnet, err := DecodeWIF(tt.wif)
+		if err != nil {
+			t.Errorf("DecodeWIF(%s): %v", tt.wif, err)
+			continue
+		}
+		if !bytes.Equal(priv, key) || compressed != tt.compressed || mainnet != tt.mainnet {
+			t.Errorf("DecodeWIF(%s) = %x, %v, %v, want %s, %v, %v", tt.wif,
+				priv, compressed, mainnet, tt.key, tt.compressed, tt.mainnet)
+		}
+	}
+}
+
+func TestWIFInvalid(t *testing.T) {
+	n := decodeHex(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	nMinusOne := decodeHex(t, "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140")
+	for _, key := range [][]byte{nil, make([]byte, 32), n, nMinusOne[1:], append(nMinusOne, 0)} {
+		if _, err := EncodeWIF(key, true, true); err == nil {
+			t.Errorf("EncodeWIF(%x) succeeded", key)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		// Bad checksum.
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTK",
+		// Invalid base58 character.
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvy0J",
+		// A P2PKH address: valid base58check, wrong length.
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		// Unknown version byte.
+		base58.CheckEncode(append([]byte{0x00}, nMinusOne...)),
+		// Bad compression flag.
+		base58.CheckEncode(append(append([]byte{wifcodec.MainnetVersion}, nMinusOne...), 0x02)),
+		// Zero and out-of-range scalars.
+		base58.CheckEncode(append([]byte{wifcodec.MainnetVersion}, make([]byte, 32)...)),
+		base58.CheckEncode(append([]byte{wifcodec.TestnetVersion}, n...)),
+		base58.CheckEncode(append([]byte{wifcodec.MainnetVersion}, bytes.Repeat([]byte{0xff}, 32)...)),
+	} {
+		if _, _, _, err := DecodeWIF(s); err == nil {
+			t.Errorf("DecodeWIF(%q) succeeded", s)
+		}
+	}
+}